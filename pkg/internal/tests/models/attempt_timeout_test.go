@@ -0,0 +1,127 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// hungAttemptTimer records the requested delays. Backoffs elapse at once, while the attempt timeout
+// elapses once the server reports a hung request, standing in for the timeout passing.
+type hungAttemptTimer struct {
+	recordingTimer
+	attemptTimeout time.Duration
+	hung           chan struct{}
+	done           chan struct{}
+}
+
+func (t *hungAttemptTimer) After(d time.Duration) <-chan time.Time {
+	if d != t.attemptTimeout {
+		return t.recordingTimer.After(d)
+	}
+
+	t.mu.Lock()
+	t.delays = append(t.delays, d)
+	t.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	go func() {
+		select {
+		case <-t.hung:
+			ch <- time.Now()
+		case <-t.done:
+		}
+	}()
+	return ch
+}
+
+// TestAttemptTimeoutRetriesHungAttempt hangs the first attempt until it is cancelled by a 10s attempt timeout,
+// then answers the retry.
+func TestAttemptTimeoutRetriesHungAttempt(t *testing.T) {
+	const attemptTimeout = 10 * time.Second
+
+	timer := &hungAttemptTimer{
+		attemptTimeout: attemptTimeout,
+		hung:           make(chan struct{}, 1),
+		done:           make(chan struct{}),
+	}
+	defer close(timer.done)
+
+	var calls int32
+	var onRetryErr error
+	var mu sync.Mutex
+	client := newMockClientWithHttpOptions(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			timer.hung <- struct{}{}
+			// The server notices the cancelled attempt once the request body is consumed
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello"}]}`))
+	}, []wx.HttpClientOption{
+		wx.WithRetryOptions(
+			wx.WithRetries(2),
+			wx.WithAttemptTimeout(attemptTimeout),
+			wx.WithTimer(timer),
+			wx.WithOnRetry(func(attempt uint, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				onRetryErr = err
+			}),
+		),
+	})
+
+	text, err := client.Complete(context.Background(), "mock-model", "Say hello")
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got: %v", err)
+	}
+	if text != "hello" {
+		t.Errorf("Expected 'hello', got %q", text)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !errors.Is(onRetryErr, wx.ErrAttemptTimeout) {
+		t.Errorf("Expected the first attempt to fail with ErrAttemptTimeout, got: %v", onRetryErr)
+	}
+	if errors.Is(onRetryErr, context.Canceled) || errors.Is(onRetryErr, context.DeadlineExceeded) {
+		t.Errorf("Expected the attempt timeout not to match a context error, got: %v", onRetryErr)
+	}
+	if class := wx.ClassifyError(onRetryErr); class != wx.ErrorClassNetwork {
+		t.Errorf("Expected the attempt timeout to be classified as network, got %v", class)
+	}
+
+	delays := timer.Delays()
+	if len(delays) == 0 || delays[0] != attemptTimeout {
+		t.Errorf("Expected the first attempt to wait for the %v attempt timeout, got delays %v", attemptTimeout, delays)
+	}
+}
+
+// TestAttemptTimeoutKeepsBodyReadable checks that the attempt context outlives a response returned in time,
+// so its body can be read after the attempt.
+func TestAttemptTimeoutKeepsBodyReadable(t *testing.T) {
+	client := newMockClientWithHttpOptions(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello"}]}`))
+	}, []wx.HttpClientOption{
+		wx.WithRetryOptions(wx.WithAttemptTimeout(time.Minute)),
+	})
+
+	text, err := client.Complete(context.Background(), "mock-model", "Say hello")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if text != "hello" {
+		t.Errorf("Expected 'hello', got %q", text)
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// cosReference returns a reference to a file of the "scoring" bucket through the cos-connection asset
+func cosReference(fileName string) wx.COSReference {
+	return wx.COSReference{ConnectionID: "cos-connection", Bucket: "scoring", FileName: fileName}
+}
+
+// batchJobHandler answers the deployment jobs endpoints, reporting the given states on successive polls
+func batchJobHandler(t *testing.T, payload *wx.BatchPayload, states ...string) http.HandlerFunc {
+	var polls int32
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		state := wx.BatchQueued
+		var failure interface{}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == wx.DeploymentJobsEndpoint:
+			json.NewDecoder(r.Body).Decode(payload)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == wx.DeploymentJobsEndpoint+"/batch-1":
+			n := int(atomic.AddInt32(&polls, 1))
+			state = states[min(n, len(states))-1]
+			if state == wx.BatchFailed {
+				failure = map[string]interface{}{
+					"errors": []map[string]string{{"code": "invalid_input", "message": "Input file not found"}},
+				}
+			}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"metadata": map[string]interface{}{"id": "batch-1", "created_at": "2024-10-01T00:00:00Z"},
+			"entity": map[string]interface{}{
+				"scoring": map[string]interface{}{
+					"status": map[string]interface{}{"state": state, "failure": failure},
+				},
+			},
+		})
+	}
+}
+
+func TestSubmitBatchAndWait(t *testing.T) {
+	var payload wx.BatchPayload
+	client := newMockClient(t, batchJobHandler(t, &payload, wx.BatchRunning, wx.BatchCompleted))
+
+	job, err := client.SubmitBatch(context.Background(), wx.BatchRequest{
+		Name:         "nightly-scoring",
+		DeploymentID: "batch-deployment",
+		Inputs:       []wx.COSReference{cosReference("input.csv")},
+		Output:       cosReference("output.csv"),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if job.ID != "batch-1" || job.Status != wx.BatchQueued {
+		t.Errorf("Expected a queued batch-1 job, got %+v", job)
+	}
+
+	if payload.Deployment.ID != "batch-deployment" || payload.ProjectID != "mock-project-id" {
+		t.Errorf("Expected the deployment and project in the request, got %+v", payload)
+	}
+	if len(payload.Scoring.InputDataReferences) != 1 ||
+		payload.Scoring.InputDataReferences[0].FileName != "input.csv" ||
+		payload.Scoring.OutputDataReference.FileName != "output.csv" ||
+		payload.Scoring.OutputDataReference.ConnectionID != "cos-connection" {
+		t.Errorf("Expected the COS references in the request, got %+v", payload.Scoring)
+	}
+
+	result, err := client.WaitForBatch(context.Background(), job.ID, wx.WithBackoff(time.Millisecond), wx.WithNoJitter())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Status != wx.BatchCompleted {
+		t.Errorf("Expected a completed job, got %+v", result)
+	}
+}
+
+func TestWaitForBatchFailed(t *testing.T) {
+	var payload wx.BatchPayload
+	client := newMockClient(t, batchJobHandler(t, &payload, wx.BatchFailed))
+
+	job, err := client.WaitForBatch(context.Background(), "batch-1", wx.WithBackoff(time.Millisecond), wx.WithNoJitter())
+	if err == nil || !strings.Contains(err.Error(), "Input file not found") {
+		t.Fatalf("Expected the failure reason in the error, got %v", err)
+	}
+
+	if job == nil || len(job.Errors) != 1 || job.Errors[0].Code != "invalid_input" {
+		t.Errorf("Expected the failed job with its errors, got %+v", job)
+	}
+}
+
+func TestSubmitBatchValidation(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s %s", r.Method, r.URL.Path)
+	})
+
+	_, err := client.SubmitBatch(context.Background(), wx.BatchRequest{DeploymentID: "batch-deployment"})
+	if err == nil {
+		t.Error("Expected an error without inputs")
+	}
+
+	_, err = client.SubmitBatch(context.Background(), wx.BatchRequest{
+		DeploymentID: "../batch",
+		Inputs:       []wx.COSReference{cosReference("input.csv")},
+	})
+	if err == nil {
+		t.Error("Expected an error for an invalid deployment ID")
+	}
+
+	if _, err := client.GetBatch(context.Background(), ""); err == nil {
+		t.Error("Expected an error for an empty job ID")
+	}
+}
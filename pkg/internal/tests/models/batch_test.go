@@ -0,0 +1,227 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// TestGenerateBatchOrderedResults generates 10 prompts with concurrency 3 and checks
+// that results keep the input order and that one failing prompt only affects its own index.
+func TestGenerateBatchOrderedResults(t *testing.T) {
+	const failingIndex = 4
+	var inFlight, maxInFlight int32
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		var payload wx.GenerateTextPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// Give the other workers a chance to overlap
+		time.Sleep(10 * time.Millisecond)
+
+		if payload.Prompt == fmt.Sprintf("prompt %d", failingIndex) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors":[{"code":"invalid_input","message":"bad prompt"}]}`))
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{
+				{"generated_text": "echo: " + payload.Prompt, "stop_reason": wx.EndOfSequenceToken},
+			},
+		})
+	})
+
+	reqs := make([]wx.GenerateTextRequest, 10)
+	for i := range reqs {
+		reqs[i] = wx.GenerateTextRequest{
+			Model:  "mock-model",
+			Prompt: fmt.Sprintf("prompt %d", i),
+		}
+	}
+
+	results, err := client.GenerateBatch(context.Background(), reqs, 3)
+	if err != nil {
+		t.Fatalf("Expected no batch error, got %v", err)
+	}
+
+	if len(results) != len(reqs) {
+		t.Fatalf("Expected %d results, got %d", len(reqs), len(results))
+	}
+
+	for i, result := range results {
+		if i == failingIndex {
+			if result.Err == nil {
+				t.Errorf("Expected an error at index %d", i)
+			}
+			continue
+		}
+
+		if result.Err != nil {
+			t.Errorf("Expected no error at index %d, got %v", i, result.Err)
+		}
+
+		expected := fmt.Sprintf("echo: prompt %d", i)
+		if result.Result.Text != expected {
+			t.Errorf("Expected %q at index %d, got %q", expected, i, result.Result.Text)
+		}
+	}
+
+	if max := atomic.LoadInt32(&maxInFlight); max > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, got %d", max)
+	}
+}
+
+// TestGenerateBatchWithPrompts verifies that a batch request with several prompts is generated like Generate does.
+func TestGenerateBatchWithPrompts(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload wx.GenerateTextPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || len(payload.Prompts) != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"results":[{"generated_text":"first"},{"generated_text":"second"}]}`))
+	})
+
+	results, err := client.GenerateBatch(context.Background(), []wx.GenerateTextRequest{
+		{Model: "mock-model", Prompts: []string{"a", "b"}},
+	}, 1)
+	if err != nil || results[0].Err != nil {
+		t.Fatalf("Expected no error, got %v and %v", err, results[0].Err)
+	}
+
+	response := results[0].Response
+	if len(response.Results) != 2 || response.Results[1].Text != "second" {
+		t.Errorf("Expected both results of the prompts, got %+v", response.Results)
+	}
+	if results[0].Result.Text != "first" {
+		t.Errorf("Expected the first result, got %q", results[0].Result.Text)
+	}
+}
+
+// TestGenerateBatchCancelledContext verifies that a cancelled context fails the batch.
+func TestGenerateBatchCancelledContext(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := client.GenerateBatch(ctx, []wx.GenerateTextRequest{
+		{Model: "mock-model", Prompt: "one"},
+		{Model: "mock-model", Prompt: "two"},
+	}, 1)
+
+	if err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	for i, result := range results {
+		if result.Err == nil {
+			t.Errorf("Expected an error at index %d", i)
+		}
+	}
+}
+
+func TestEmbedAll(t *testing.T) {
+	var calls int32
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var payload wx.EmbeddingPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		// embed each input as a vector holding its number
+		results := make([]map[string]interface{}, len(payload.Inputs))
+		for i, input := range payload.Inputs {
+			n, _ := strconv.Atoi(input)
+			results[i] = map[string]interface{}{"embedding": []float64{float64(n)}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	})
+
+	inputs := make([]string, 250)
+	for i := range inputs {
+		inputs[i] = strconv.Itoa(i)
+	}
+
+	vectors, err := client.EmbedAll(context.Background(), "model", inputs, 100)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 embedding calls, got %d", got)
+	}
+
+	if len(vectors) != len(inputs) {
+		t.Fatalf("Expected %d vectors, got %d", len(inputs), len(vectors))
+	}
+
+	for i, vector := range vectors {
+		if len(vector) != 1 || vector[0] != float64(i) {
+			t.Fatalf("Expected vector %d to match its input, got %v", i, vector)
+		}
+	}
+}
+
+func TestEmbedAllReportsFailedBatch(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload wx.EmbeddingPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		if payload.Inputs[0] == "100" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		results := make([]map[string]interface{}, len(payload.Inputs))
+		for i := range payload.Inputs {
+			results[i] = map[string]interface{}{"embedding": []float64{0}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	})
+
+	inputs := make([]string, 250)
+	for i := range inputs {
+		inputs[i] = strconv.Itoa(i)
+	}
+
+	_, err := client.EmbedAll(context.Background(), "model", inputs, 100)
+
+	var batchErr *wx.EmbedBatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected EmbedBatchError, got %v", err)
+	}
+
+	if batchErr.Batch != 1 || batchErr.Start != 100 || batchErr.End != 200 {
+		t.Errorf("Expected batch 1 (inputs 100-199) to fail, got %+v", batchErr)
+	}
+
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) {
+		t.Errorf("Expected the batch error to wrap the WatsonxError, got %v", err)
+	}
+}
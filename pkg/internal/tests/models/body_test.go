@@ -0,0 +1,75 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// TestConnectionReuse verifies that response bodies are drained so sequential requests share one connection.
+func TestConnectionReuse(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// json.Encoder appends a newline that the client's decoder leaves unread
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{{"generated_text": "ok"}},
+		})
+	})
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	var dials int32
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	dialer := &net.Dialer{}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	client, err := wx.NewClient(
+		wx.WithURL(host),
+		wx.WithIAM(host),
+		wx.WithWatsonxAPIKey("mock-api-key"),
+		wx.WithWatsonxProjectID("mock-project-id"),
+		wx.WithHttpClient(wx.NewHttpClient(
+			wx.WithTransport(transport),
+			wx.WithRetryOptions(wx.WithRetries(1)),
+		)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.GenerateText("model", "prompt"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&dials); got != 1 {
+		t.Errorf("Expected a single connection to be reused, got %d dials", got)
+	}
+}
+
+func TestEmbeddingResponseDecodedAsStream(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model_id":"mock-model","results":[{"embedding":[0.1,0.2],"input":"a"},{"embedding":[0.3]}],` +
+			`"created_at":"2024-05-01T10:00:00Z","input_token_count":7}`))
+	})
+
+	response, err := client.EmbedDocuments("mock-model", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Model != "mock-model" || response.InputTokenCount != 7 || response.CreatedAt.IsZero() {
+		t.Errorf("Expected the fields around the results to be decoded, got %+v", response)
+	}
+	if len(response.Results) != 2 || response.Results[0].Input != "a" || len(response.Results[1].Embedding) != 1 {
+		t.Errorf("Expected both results to be decoded, got %+v", response.Results)
+	}
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// TestCircuitBreakerOpensAndProbes drives the breaker open with failing requests,
+// checks that it short-circuits, then observes a half-open probe after the reset timeout.
+func TestCircuitBreakerOpensAndProbes(t *testing.T) {
+	var hits int32
+	var healthy atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	failureThreshold := 2
+	resetTimeout := 100 * time.Millisecond
+
+	client := wx.NewHttpClient(
+		wx.WithCircuitBreaker(failureThreshold, resetTimeout),
+		wx.WithRetryOptions(wx.WithRetries(1), wx.WithBackoff(0), wx.WithMaxJitter(0)),
+	)
+
+	doRequest := func() (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		return client.DoWithRetry(req)
+	}
+
+	for i := 0; i < failureThreshold; i++ {
+		_, err := doRequest()
+		var wxErr *wx.WatsonxError
+		if !errors.As(err, &wxErr) {
+			t.Fatalf("Expected WatsonxError on failure %d, got %v", i+1, err)
+		}
+	}
+
+	hitsWhenOpened := atomic.LoadInt32(&hits)
+
+	start := time.Now()
+	_, err := doRequest()
+	if !errors.Is(err, wx.ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected immediate return while open, took %v", elapsed)
+	}
+	if atomic.LoadInt32(&hits) != hitsWhenOpened {
+		t.Errorf("Expected no request to reach the server while open")
+	}
+
+	time.Sleep(resetTimeout + 20*time.Millisecond)
+	healthy.Store(true)
+
+	resp, err := doRequest()
+	if err != nil {
+		t.Fatalf("Expected half-open probe to succeed, got %v", err)
+	}
+	resp.Body.Close()
+
+	if atomic.LoadInt32(&hits) != hitsWhenOpened+1 {
+		t.Errorf("Expected exactly one probe request to reach the server")
+	}
+}
+
+// TestCircuitBreakerFailedProbeReopens verifies that a failed half-open probe reopens the breaker.
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	cb := wx.NewCircuitBreaker(1, 50*time.Millisecond)
+
+	cb.RecordFailure()
+	if cb.State() != wx.CircuitOpen {
+		t.Fatalf("Expected breaker to be open, got %v", cb.State())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("Expected probe to be allowed, got %v", err)
+	}
+	if cb.State() != wx.CircuitHalfOpen {
+		t.Fatalf("Expected breaker to be half-open, got %v", cb.State())
+	}
+	if err := cb.Allow(); !errors.Is(err, wx.ErrCircuitOpen) {
+		t.Fatalf("Expected a second concurrent probe to be rejected, got %v", err)
+	}
+
+	cb.RecordFailure()
+	if cb.State() != wx.CircuitOpen {
+		t.Fatalf("Expected failed probe to reopen the breaker, got %v", cb.State())
+	}
+}
+
+// failingBody is a request body whose reads fail
+type failingBody struct{}
+
+func (failingBody) Read([]byte) (int, error) {
+	return 0, errors.New("body read failed")
+}
+
+// TestCircuitBreakerProbeNotHeldByPreflightFailure verifies that a half-open request failing before it is sent,
+// here because its body cannot be read, does not keep the probe and block every later request.
+func TestCircuitBreakerProbeNotHeldByPreflightFailure(t *testing.T) {
+	var healthy atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	resetTimeout := 50 * time.Millisecond
+	client := wx.NewHttpClient(
+		wx.WithCircuitBreaker(1, resetTimeout),
+		wx.WithRetryOptions(wx.WithRetries(1), wx.WithNoJitter()),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := client.DoWithRetry(req); err == nil {
+		t.Fatal("Expected the first request to fail")
+	}
+
+	time.Sleep(resetTimeout + 20*time.Millisecond)
+	healthy.Store(true)
+
+	req, _ = http.NewRequest(http.MethodPost, server.URL, failingBody{})
+	if _, err := client.DoWithRetry(req); err == nil || errors.Is(err, wx.ErrCircuitOpen) {
+		t.Fatalf("Expected the body read error, got %v", err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.DoWithRetry(req)
+	if err != nil {
+		t.Fatalf("Expected the next request to probe the service, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+// TestCircuitBreakerRecordsRawFailedResponses verifies that failed responses returned without an error
+// by WithRawResponses still open the breaker.
+func TestCircuitBreakerRecordsRawFailedResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := wx.NewHttpClient(
+		wx.WithCircuitBreaker(2, time.Minute),
+		wx.WithRetryOptions(wx.WithRetries(1), wx.WithNoJitter(), wx.WithRawResponses()),
+	)
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.DoWithRetry(req)
+		if err != nil {
+			t.Fatalf("Expected the raw response without error, got %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := client.DoWithRetry(req); !errors.Is(err, wx.ErrCircuitOpen) {
+		t.Fatalf("Expected the failed raw responses to open the breaker, got %v", err)
+	}
+}
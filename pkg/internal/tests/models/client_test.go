@@ -0,0 +1,348 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestClientWithBaseURL(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, wx.GenerateTextEndpoint):
+			w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+		case strings.HasSuffix(r.URL.Path, wx.EmbeddingEndpoint):
+			w.Write([]byte(`{"results":[{"embedding":[0.1,0.2]}]}`))
+		case strings.HasSuffix(r.URL.Path, wx.ChatEndpoint):
+			w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	client, err := wx.NewClient(
+		wx.WithBaseURL(server.URL+"/gateway/"),
+		wx.WithURL("unused.ml.cloud.ibm.com"),
+		wx.WithIAM(strings.TrimPrefix(server.URL, "https://")),
+		wx.WithWatsonxAPIKey("mock-api-key"),
+		wx.WithWatsonxProjectID("mock-project-id"),
+		wx.WithHttpClient(wx.NewHttpClient(
+			wx.WithTransport(server.Client().Transport),
+			wx.WithRetryOptions(wx.WithRetries(1)),
+		)),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error creating client, got %v", err)
+	}
+
+	if _, err := client.GenerateText("mock-model", "Hello"); err != nil {
+		t.Fatalf("Expected no error for generation, got %v", err)
+	}
+
+	if _, err := client.EmbedQuery("mock-model", "Hello"); err != nil {
+		t.Fatalf("Expected no error for embedding, got %v", err)
+	}
+
+	if _, err := client.SimpleChat("mock-model", "Hello"); err != nil {
+		t.Fatalf("Expected no error for chat, got %v", err)
+	}
+
+	expected := []string{
+		"/gateway" + wx.GenerateTextEndpoint,
+		"/gateway" + wx.EmbeddingEndpoint,
+		"/gateway" + wx.ChatEndpoint,
+	}
+
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %d requests to the base URL host, got %v", len(expected), paths)
+	}
+
+	for i, path := range expected {
+		if paths[i] != path {
+			t.Errorf("Expected request %d to target %s, got %s", i, path, paths[i])
+		}
+	}
+}
+
+func TestClientWithInvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"gateway.internal", "ftp://gateway.internal", "https://", "://bad"} {
+		_, err := wx.NewClient(
+			wx.WithBaseURL(baseURL),
+			wx.WithWatsonxAPIKey("mock-api-key"),
+			wx.WithWatsonxProjectID("mock-project-id"),
+		)
+		if err == nil {
+			t.Errorf("Expected error for invalid base URL %q, got nil", baseURL)
+		}
+	}
+}
+
+func TestClientDefaultUserAgent(t *testing.T) {
+	var userAgent string
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	})
+
+	if _, err := client.GenerateText("mock-model", "Hello"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if userAgent != "watsonx-go/"+wx.Version {
+		t.Errorf("Expected default User-Agent %q, got %q", wx.DefaultUserAgent, userAgent)
+	}
+}
+
+func TestClientWithUserAgent(t *testing.T) {
+	var userAgent string
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	}, wx.WithUserAgent("my-app/2.1 "+wx.DefaultUserAgent))
+
+	if _, err := client.GenerateText("mock-model", "Hello"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if userAgent != "my-app/2.1 watsonx-go/"+wx.Version {
+		t.Errorf("Expected overridden User-Agent, got %q", userAgent)
+	}
+}
+
+// closeTrackingTransport counts calls to CloseIdleConnections
+type closeTrackingTransport struct {
+	http.RoundTripper
+	closed int
+}
+
+func (c *closeTrackingTransport) CloseIdleConnections() {
+	c.closed++
+}
+
+func TestClientCloseReleasesIdleConnections(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	})
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	transport := &closeTrackingTransport{RoundTripper: server.Client().Transport}
+
+	client, err := wx.NewClient(
+		wx.WithURL(host),
+		wx.WithIAM(host),
+		wx.WithWatsonxAPIKey("mock-api-key"),
+		wx.WithWatsonxProjectID("mock-project-id"),
+		wx.WithHttpClient(wx.NewHttpClient(wx.WithTransport(transport))),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error creating client, got %v", err)
+	}
+
+	if _, err := client.GenerateText("mock-model", "Hello"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Expected no error closing client, got %v", err)
+	}
+
+	if transport.closed != 1 {
+		t.Errorf("Expected CloseIdleConnections to be called once, got %d", transport.closed)
+	}
+}
+
+func TestHttpClientCloseWithMiddleware(t *testing.T) {
+	transport := &closeTrackingTransport{RoundTripper: http.DefaultTransport}
+	passThrough := func(next http.RoundTripper) http.RoundTripper {
+		return wx.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return next.RoundTrip(req)
+		})
+	}
+
+	client := wx.NewHttpClient(wx.WithTransport(transport), wx.WithMiddleware(passThrough))
+	if err := client.Close(); err != nil {
+		t.Fatalf("Expected no error closing client, got %v", err)
+	}
+
+	if transport.closed != 1 {
+		t.Errorf("Expected CloseIdleConnections to reach the transport below the middleware, got %d calls", transport.closed)
+	}
+}
+
+func TestRegionHost(t *testing.T) {
+	expected := map[wx.IBMCloudRegion]string{
+		wx.RegionDallas:    "us-south.ml.cloud.ibm.com",
+		wx.RegionFrankfurt: "eu-de.ml.cloud.ibm.com",
+		wx.RegionTokyo:     "jp-tok.ml.cloud.ibm.com",
+		wx.RegionLondon:    "eu-gb.ml.cloud.ibm.com",
+		wx.RegionSydney:    "au-syd.ml.cloud.ibm.com",
+		wx.RegionToronto:   "ca-tor.ml.cloud.ibm.com",
+	}
+
+	for region, host := range expected {
+		got, err := wx.RegionHost(region)
+		if err != nil {
+			t.Errorf("Expected no error for region %s, got %v", region, err)
+			continue
+		}
+		if got != host {
+			t.Errorf("Expected region %s to resolve to %s, got %s", region, host, got)
+		}
+	}
+}
+
+func TestClientWithUnknownRegion(t *testing.T) {
+	t.Setenv(wx.WatsonxURLEnvVarName, "")
+
+	_, err := wx.NewClient(
+		wx.WithRegion("mars-north"),
+		wx.WithWatsonxAPIKey("mock-api-key"),
+		wx.WithWatsonxProjectID("mock-project-id"),
+	)
+	if err == nil || !strings.Contains(err.Error(), "mars-north") {
+		t.Fatalf("Expected an unknown region error, got %v", err)
+	}
+}
+
+func TestClientWithUnlistedRegionAndURL(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	}, wx.WithRegion("mars-north"))
+
+	if _, err := client.GenerateText("model", "prompt"); err != nil {
+		t.Fatalf("Expected the URL to be used for an unlisted region, got %v", err)
+	}
+}
+
+func TestClientAPIVersionOnAllRequests(t *testing.T) {
+	var mu sync.Mutex
+	versions := map[string]string{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		versions[r.URL.Path] = r.URL.Query().Get("version")
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case wx.GenerateTextEndpoint:
+			w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+		case wx.EmbeddingEndpoint:
+			w.Write([]byte(`{"results":[{"embedding":[0.1,0.2]}]}`))
+		case wx.ChatEndpoint:
+			w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, wx.WithAPIVersion("2025-02-11"))
+
+	if _, err := client.GenerateText("model", "prompt"); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if _, err := client.EmbedQuery("model", "text"); err != nil {
+		t.Fatalf("EmbedQuery failed: %v", err)
+	}
+	if _, err := client.SimpleChat("model", "hi"); err != nil {
+		t.Fatalf("SimpleChat failed: %v", err)
+	}
+
+	for _, endpoint := range []string{wx.GenerateTextEndpoint, wx.EmbeddingEndpoint, wx.ChatEndpoint} {
+		if versions[endpoint] != "2025-02-11" {
+			t.Errorf("Expected version 2025-02-11 on %s, got %q", endpoint, versions[endpoint])
+		}
+	}
+}
+
+func TestClientDefaultAPIVersion(t *testing.T) {
+	var version string
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		version = r.URL.Query().Get("version")
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	}, wx.WithAPIVersion(""))
+
+	if _, err := client.GenerateText("model", "prompt"); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	if version != wx.DefaultAPIVersion {
+		t.Errorf("Expected default version %s, got %q", wx.DefaultAPIVersion, version)
+	}
+}
+
+func TestClientInvalidAPIVersion(t *testing.T) {
+	for _, version := range []string{"2024-13-01", "2024-02-30", "20240520", "latest"} {
+		_, err := wx.NewClient(
+			wx.WithURL("us-south.ml.cloud.ibm.com"),
+			wx.WithAPIVersion(version),
+			wx.WithWatsonxAPIKey("mock-api-key"),
+			wx.WithWatsonxProjectID("mock-project-id"),
+		)
+		if err == nil || !strings.Contains(err.Error(), "invalid API version") {
+			t.Errorf("Expected an invalid API version error for %q, got %v", version, err)
+		}
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	var query url.Values
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello"}]}`))
+	})
+
+	t.Setenv(wx.WatsonxAPIKeyEnvVarName, "env-api-key")
+	t.Setenv(wx.WatsonxProjectIDEnvVarName, "env-project-id")
+	t.Setenv(wx.WatsonxBaseURLEnvVarName, server.URL)
+	t.Setenv(wx.WatsonxIAMEnvVarName, strings.TrimPrefix(server.URL, "https://"))
+	t.Setenv(wx.WatsonxAPIVersionEnvVarName, "2024-10-01")
+
+	client, err := wx.NewClientFromEnv(wx.WithHttpClient(wx.NewHttpClient(wx.WithTransport(server.Client().Transport))))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	text, err := client.Complete(context.Background(), "mock-model", "Say hello")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if text != "hello" {
+		t.Errorf("Expected the generated text, got %q", text)
+	}
+
+	if query.Get("version") != "2024-10-01" {
+		t.Errorf("Expected the API version from the environment, got %q", query.Get("version"))
+	}
+}
+
+func TestNewClientFromEnvMissing(t *testing.T) {
+	t.Setenv(wx.WatsonxAPIKeyEnvVarName, "")
+	t.Setenv(wx.WatsonxProjectIDEnvVarName, "env-project-id")
+
+	_, err := wx.NewClientFromEnv()
+	if err == nil || !strings.Contains(err.Error(), wx.WatsonxAPIKeyEnvVarName) {
+		t.Errorf("Expected an error naming %s, got %v", wx.WatsonxAPIKeyEnvVarName, err)
+	}
+
+	t.Setenv(wx.WatsonxProjectIDEnvVarName, "")
+	t.Setenv(wx.WatsonxSpaceIDEnvVarName, "")
+
+	_, err = wx.NewClientFromEnv()
+	if err == nil || !strings.Contains(err.Error(), wx.WatsonxProjectIDEnvVarName) {
+		t.Errorf("Expected an error naming %s, got %v", wx.WatsonxProjectIDEnvVarName, err)
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// recordingCodec encodes with encoding/json and records the types it was invoked with
+type recordingCodec struct {
+	mu          sync.Mutex
+	marshaled   []string
+	unmarshaled []string
+}
+
+func (c *recordingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.mu.Lock()
+	c.marshaled = append(c.marshaled, fmt.Sprintf("%T", v))
+	c.mu.Unlock()
+	return json.Marshal(v)
+}
+
+func (c *recordingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.mu.Lock()
+	c.unmarshaled = append(c.unmarshaled, fmt.Sprintf("%T", v))
+	c.mu.Unlock()
+	return json.Unmarshal(data, v)
+}
+
+func TestCodecRequestAndResponse(t *testing.T) {
+	codec := &recordingCodec{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"generated_text":"hello"}]}`))
+	}, wx.WithCodec(codec))
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:  "mock-model",
+		Prompt: "Say hello",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.FirstText() != "hello" {
+		t.Errorf("Expected the response to be decoded, got %+v", response)
+	}
+
+	if len(codec.marshaled) != 1 || !strings.HasSuffix(codec.marshaled[0], "models.GenerateTextPayload") {
+		t.Errorf("Expected the codec to marshal the payload once, got %v", codec.marshaled)
+	}
+	if len(codec.unmarshaled) != 1 || !strings.HasSuffix(strings.ToLower(codec.unmarshaled[0]), "generatetextresponse") {
+		t.Errorf("Expected the codec to unmarshal the response once, got %v", codec.unmarshaled)
+	}
+}
+
+func TestCodecErrorResponse(t *testing.T) {
+	codec := &recordingCodec{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors":[{"code":"invalid_input","message":"bad prompt"}],"status_code":400}`))
+	}, wx.WithCodec(codec))
+
+	_, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:  "mock-model",
+		Prompt: "Say hello",
+	})
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+
+	var apiErr *wx.WatsonxError
+	if !errors.As(err, &apiErr) || len(apiErr.Errors) != 1 || apiErr.Errors[0].Code != "invalid_input" {
+		t.Fatalf("Expected a decoded WatsonxError, got %v", err)
+	}
+
+	if len(codec.unmarshaled) != 1 {
+		t.Errorf("Expected the codec to decode the error body, got %v", codec.unmarshaled)
+	}
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// expiredClock is a Clock past the expiration of the mock IAM tokens, so every request refreshes the token
+type expiredClock struct{}
+
+func (expiredClock) Now() time.Time {
+	return time.Now().Add(2 * time.Hour)
+}
+
+// TestClientConcurrentUse shares one client between goroutines that generate, embed and chat
+// while the token is refreshed; run with -race to detect data races.
+func TestClientConcurrentUse(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case wx.GenerateTextEndpoint:
+			w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello"}]}`))
+		case wx.EmbeddingEndpoint:
+			w.Write([]byte(`{"model_id": "mock-model", "results": [{"embedding": [0.1, 0.2]}], "input_token_count": 2}`))
+		case wx.ChatEndpoint:
+			w.Write([]byte(`{"id": "chat-1", "model_id": "mock-model", "choices": [{"index": 0, "message": {"role": "assistant", "content": "hi"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, wx.WithClientClock(expiredClock{}))
+
+	const goroutines = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+			if _, err := client.Complete(context.Background(), "mock-model", "Say hello"); err != nil {
+				t.Errorf("Generate failed: %v", err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			if _, err := client.EmbedQuery("mock-model", "hello"); err != nil {
+				t.Errorf("Embed failed: %v", err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			if _, err := client.Chat("mock-model", []wx.ChatMessage{wx.CreateUserMessage("hello")}); err != nil {
+				t.Errorf("Chat failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHttpClientMaxConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, peak int
+
+	transport := wx.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+
+	client := wx.NewHttpClient(
+		wx.WithTransport(transport),
+		wx.WithRetryOptions(wx.WithRetries(1)),
+		wx.WithMaxConcurrency(2),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			resp, err := client.DoWithRetry(req)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", peak)
+	}
+}
+
+func TestHttpClientMaxConcurrencyCancel(t *testing.T) {
+	release := make(chan struct{})
+	transport := wx.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-release
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+
+	client := wx.NewHttpClient(wx.WithTransport(transport), wx.WithMaxConcurrency(1))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	// Wait for the first request to take the only slot
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the waiting request to fail with its context error, got %v", err)
+	}
+
+	close(release)
+	<-done
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestCOSReferenceJSON(t *testing.T) {
+	ref := wx.COSReference{ConnectionID: "cos-connection", Bucket: "documents", FileName: "reports/q3.pdf"}
+
+	data, err := json.Marshal(ref)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `{"type":"connection_asset","connection":{"id":"cos-connection"},"location":{"bucket":"documents","file_name":"reports/q3.pdf"}}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	var decoded wx.COSReference
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded != ref {
+		t.Errorf("Expected %+v after a round trip, got %+v", ref, decoded)
+	}
+}
+
+func TestCOSReferenceValidate(t *testing.T) {
+	tests := []struct {
+		ref   wx.COSReference
+		field string
+	}{
+		{wx.COSReference{ConnectionID: "cos-connection", Bucket: "documents", FileName: "report.pdf"}, ""},
+		{wx.COSReference{Bucket: "documents", FileName: "report.pdf"}, "connection"},
+		{wx.COSReference{ConnectionID: "cos-connection", FileName: "report.pdf"}, "bucket"},
+		{wx.COSReference{ConnectionID: "cos-connection", Bucket: "documents"}, "file name"},
+	}
+
+	for _, tt := range tests {
+		err := tt.ref.Validate()
+		if tt.field == "" {
+			if err != nil {
+				t.Errorf("Expected %+v to be valid, got %v", tt.ref, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.field) {
+			t.Errorf("Expected %+v to be rejected for its %s, got %v", tt.ref, tt.field, err)
+		}
+	}
+}
+
+func TestExtractTextRejectsEmptyBucket(t *testing.T) {
+	calls := 0
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+
+	_, err := client.ExtractText(context.Background(), wx.ExtractionRequest{
+		Document: wx.COSReference{ConnectionID: "cos-connection", FileName: "report.pdf"},
+		Results:  wx.COSReference{ConnectionID: "cos-connection", Bucket: "documents", FileName: "report.md"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "bucket") {
+		t.Errorf("Expected the empty bucket to be rejected, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no request to be sent, got %d", calls)
+	}
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestCostEstimator(t *testing.T) {
+	estimator := wx.NewCostEstimator(map[string]wx.ModelPrice{
+		"ibm/granite-13b-instruct-v2": {InputPer1K: 0.0006, OutputPer1K: 0.0018},
+	})
+
+	usage := wx.GenerationUsage{InputTokenCount: 2000, GeneratedTokenCount: 500}
+
+	// 2 * 0.0006 + 0.5 * 0.0018
+	if got := estimator.Estimate("ibm/granite-13b-instruct-v2", usage); math.Abs(got-0.0021) > 1e-12 {
+		t.Errorf("Expected a cost of 0.0021, got %v", got)
+	}
+
+	if got := estimator.Estimate("unknown/model", usage); got != 0 {
+		t.Errorf("Expected no cost for a model without a price, got %v", got)
+	}
+
+	estimator.Record("ibm/granite-13b-instruct-v2", usage)
+	estimator.Record("ibm/granite-13b-instruct-v2", wx.GenerationUsage{InputTokenCount: 1000})
+	if got := estimator.Total(); math.Abs(got-0.0027) > 1e-12 {
+		t.Errorf("Expected a cumulative cost of 0.0027, got %v", got)
+	}
+}
+
+func TestGenerateTextResponseUsage(t *testing.T) {
+	response := wx.GenerateTextResponse{Results: []wx.GenerateTextResult{
+		{InputTokenCount: 10, GeneratedTokenCount: 4},
+		{InputTokenCount: 12, GeneratedTokenCount: 6},
+	}}
+
+	expected := wx.GenerationUsage{InputTokenCount: 22, GeneratedTokenCount: 10}
+	if got := response.Usage(); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
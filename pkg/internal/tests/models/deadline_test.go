@@ -0,0 +1,75 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// TestContextWithDeadline validates that one deadline sets the time limit and aborts the request
+func TestContextWithDeadline(t *testing.T) {
+	const timeout = time.Second
+
+	payloads := make(chan wx.GenerateTextPayload, 3)
+	client := newMockClientWithHttpOptions(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload wx.GenerateTextPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads <- payload
+		<-r.Context().Done()
+	}, []wx.HttpClientOption{wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(10*time.Millisecond))})
+
+	ctx, cancel := wx.ContextWithDeadline(context.Background(), time.Now().Add(timeout))
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Complete(ctx, "mock-model", "Say hello")
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to abort the call, got %v", err)
+	}
+
+	if elapsed < timeout-100*time.Millisecond || elapsed > timeout+500*time.Millisecond {
+		t.Errorf("Expected the call to abort at ~%v, took %v", timeout, elapsed)
+	}
+
+	payload := <-payloads
+	timeLimit := payload.Parameters.TimeLimit
+	if timeLimit == nil || *timeLimit > uint(timeout.Milliseconds()) || *timeLimit < uint(timeout.Milliseconds())-100 {
+		t.Errorf("Expected a time limit of ~%v from the deadline, got %v", timeout, timeLimit)
+	}
+}
+
+// TestContextWithDeadlineStopsRetries validates that a retry that could not start before the deadline is not waited for
+func TestContextWithDeadlineStopsRetries(t *testing.T) {
+	var calls int32
+	client := newMockClientWithHttpOptions(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, []wx.HttpClientOption{wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(10*time.Millisecond))})
+
+	ctx, cancel := wx.ContextWithDeadline(context.Background(), time.Now().Add(5*time.Second))
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Complete(ctx, "mock-model", "Say hello")
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the retry to stop immediately, took %v", elapsed)
+	}
+
+	if !errors.Is(err, wx.ErrMaxElapsedTime) {
+		t.Errorf("Expected ErrMaxElapsedTime, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls)
+	}
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// largeEmbeddingResponse returns an embedding response for a batch of 200 768-dimension vectors (about 1 MB)
+func largeEmbeddingResponse() []byte {
+	var sb strings.Builder
+	sb.WriteString(`{"model_id":"mock-model","input_token_count":4000,"results":[`)
+	for i := 0; i < 200; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(`{"embedding":[`)
+		for j := 0; j < 768; j++ {
+			if j > 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, "%.6f", float64(j)/1000)
+		}
+		sb.WriteString("]}")
+	}
+	sb.WriteString("]}")
+	return []byte(sb.String())
+}
+
+// BenchmarkDecodeLargeEmbeddingResponse measures the allocations of decoding a large embedding batch.
+// The embeddings are decoded one at a time as the body is read, so the body is never buffered whole.
+// The response is served from memory so that only the client side is measured.
+func BenchmarkDecodeLargeEmbeddingResponse(b *testing.B) {
+	body := largeEmbeddingResponse()
+	token := fmt.Sprintf(`{"access_token":"mock-token","expiration":%d}`, time.Now().Add(time.Hour).Unix())
+
+	transport := wx.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		payload := body
+		if req.URL.Path == wx.TokenPath {
+			payload = []byte(token)
+		}
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Length": {strconv.Itoa(len(payload))}},
+			ContentLength: int64(len(payload)),
+			Body:          io.NopCloser(bytes.NewReader(payload)),
+			Request:       req,
+		}, nil
+	})
+
+	client := newMockClientWithHttpOptions(b, nil, []wx.HttpClientOption{wx.WithTransport(transport)})
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		response, err := client.EmbedQuery("mock-model", "hello")
+		if err != nil {
+			b.Fatalf("Expected no error, got %v", err)
+		}
+		if len(response.Results) != 200 {
+			b.Fatalf("Expected 200 embeddings, got %d", len(response.Results))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestDefaultModelUsedWhenOmitted(t *testing.T) {
+	var body map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	}, wx.WithDefaultModel("ibm/granite-13b-instruct-v2"))
+
+	if _, err := client.Generate(context.Background(), wx.GenerateTextRequest{Prompt: "Hello"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body["model_id"] != "ibm/granite-13b-instruct-v2" {
+		t.Errorf("Expected the default model in the request, got %v", body["model_id"])
+	}
+
+	if _, err := client.GenerateText("ibm/granite-3-8b-instruct", "Hello"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body["model_id"] != "ibm/granite-3-8b-instruct" {
+		t.Errorf("Expected the explicit model to override the default, got %v", body["model_id"])
+	}
+}
+
+func TestDefaultModelForChatAndEmbeddings(t *testing.T) {
+	models := map[string]interface{}{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		models[r.URL.Path] = body["model_id"]
+
+		switch r.URL.Path {
+		case wx.EmbeddingEndpoint:
+			w.Write([]byte(`{"results":[{"embedding":[0.1,0.2]}]}`))
+		case wx.ChatEndpoint:
+			w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, wx.WithDefaultModel("default-model"))
+
+	if _, err := client.EmbedQuery("", "text"); err != nil {
+		t.Fatalf("EmbedQuery failed: %v", err)
+	}
+	if _, err := client.SimpleChat("", "hi"); err != nil {
+		t.Fatalf("SimpleChat failed: %v", err)
+	}
+
+	for _, endpoint := range []string{wx.EmbeddingEndpoint, wx.ChatEndpoint} {
+		if models[endpoint] != "default-model" {
+			t.Errorf("Expected the default model on %s, got %v", endpoint, models[endpoint])
+		}
+	}
+}
+
+func TestNoModelIsRequestError(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s", r.URL.Path)
+	})
+
+	_, generateErr := client.Generate(context.Background(), wx.GenerateTextRequest{Prompt: "Hello"})
+	_, embedErr := client.EmbedQuery("", "text")
+	_, chatErr := client.SimpleChat("", "hi")
+
+	for name, err := range map[string]error{"generate": generateErr, "embed": embedErr, "chat": chatErr} {
+		var requestErr *wx.RequestError
+		if !errors.As(err, &requestErr) {
+			t.Errorf("Expected a *RequestError from %s, got %v", name, err)
+		}
+	}
+}
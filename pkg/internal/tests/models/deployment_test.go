@@ -0,0 +1,254 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateFromDeployment(t *testing.T) {
+	deploymentID := "my-deployment-123"
+
+	var path string
+	var payload map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"results":[{"generated_text":"deployed answer","stop_reason":"eos_token"}]}`))
+	})
+
+	result, err := client.GenerateFromDeployment(context.Background(), deploymentID, wx.GenerateTextRequest{
+		Model:   "ignored-model",
+		Prompt:  "Hello",
+		Options: []wx.GenerateOption{wx.WithMaxNewTokens(50)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedPath := fmt.Sprintf(wx.DeploymentGenerateTextEndpointFormat, deploymentID)
+	if path != expectedPath {
+		t.Errorf("Expected path %s, got %s", expectedPath, path)
+	}
+
+	if _, found := payload["model_id"]; found {
+		t.Error("Expected model_id to be absent from the body")
+	}
+
+	if payload["input"] != "Hello" {
+		t.Errorf("Expected input in body, got %v", payload["input"])
+	}
+
+	parameters, _ := payload["parameters"].(map[string]interface{})
+	if parameters["max_new_tokens"] != float64(50) {
+		t.Errorf("Expected parameters in body, got %v", payload["parameters"])
+	}
+
+	if result.Text != "deployed answer" {
+		t.Errorf("Expected generated text, got %q", result.Text)
+	}
+}
+
+func TestGenerateFromDeploymentEmptyID(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent")
+	})
+
+	_, err := client.GenerateFromDeployment(context.Background(), "", wx.GenerateTextRequest{Prompt: "Hello"})
+	if err == nil {
+		t.Fatal("Expected error for empty deployment ID, got nil")
+	}
+}
+
+func TestGenerateWithTunedModel(t *testing.T) {
+	assetID := "0f4a2c6e-tuned-asset"
+
+	var path string
+	var payload map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"results":[{"generated_text":"tuned"}]}`))
+	})
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:   "ibm/granite-13b-instruct-v2",
+		Prompt:  "Hello",
+		Options: []wx.GenerateOption{wx.WithTunedModel(assetID), wx.WithMaxNewTokens(10)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedPath := fmt.Sprintf(wx.DeploymentGenerateTextEndpointFormat, assetID)
+	if path != expectedPath {
+		t.Errorf("Expected path %s, got %s", expectedPath, path)
+	}
+
+	if _, ok := payload["model_id"]; ok {
+		t.Errorf("Expected no model_id in the tuned model request, got %v", payload["model_id"])
+	}
+
+	if response.FirstText() != "tuned" {
+		t.Errorf("Expected tuned, got %q", response.FirstText())
+	}
+}
+
+func TestGenerateWithInvalidTunedModel(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s", r.URL.Path)
+	})
+
+	for _, assetID := range []string{"../models", "asset/with/slashes", "asset?x=1"} {
+		_, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+			Prompt:  "Hello",
+			Options: []wx.GenerateOption{wx.WithTunedModel(assetID)},
+		})
+		if err == nil {
+			t.Errorf("Expected an error for asset ID %q", assetID)
+		}
+	}
+}
+
+func TestListDeployments(t *testing.T) {
+	var queries []url.Values
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != wx.DeploymentEndpoint {
+			t.Errorf("Expected GET %s, got %s %s", wx.DeploymentEndpoint, r.Method, r.URL.Path)
+		}
+		queries = append(queries, r.URL.Query())
+
+		if r.URL.Query().Get("start") == "" {
+			w.Write([]byte(`{
+				"resources": [
+					{"metadata": {"id": "dep-1", "name": "tuned"}, "entity": {"asset": {"id": "asset-1"}, "status": {"state": "ready"}}},
+					{"metadata": {"id": "dep-2", "name": "base"}, "entity": {"base_model_id": "ibm/granite-13b-chat-v2", "status": {"state": "initializing"}}}
+				],
+				"next": {"href": "/ml/v4/deployments?start=token-2&limit=2"}
+			}`))
+			return
+		}
+		w.Write([]byte(`{"resources": [{"metadata": {"id": "dep-3"}, "entity": {"asset": {"id": "asset-3"}, "status": {"state": "failed"}}}]}`))
+	})
+
+	deployments, err := client.ListDeployments(context.Background(), wx.ListDeploymentsOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []wx.Deployment{
+		{ID: "dep-1", Name: "tuned", AssetID: "asset-1", State: "ready"},
+		{ID: "dep-2", Name: "base", BaseModelID: "ibm/granite-13b-chat-v2", State: "initializing"},
+		{ID: "dep-3", AssetID: "asset-3", State: "failed"},
+	}
+	if !reflect.DeepEqual(deployments, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, deployments)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected two page requests, got %d", len(queries))
+	}
+	if queries[0].Get("project_id") != "mock-project-id" || queries[0].Get("limit") != "2" {
+		t.Errorf("Expected the project and limit in the query, got %v", queries[0])
+	}
+	if queries[1].Get("start") != "token-2" {
+		t.Errorf("Expected the second page to start at the next token, got %v", queries[1])
+	}
+}
+
+func TestGenerateFromPromptTemplate(t *testing.T) {
+	templateID := "prompt-template-deployment"
+
+	var path string
+	var payload map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"results":[{"generated_text":"Bonjour","stop_reason":"eos_token"}]}`))
+	})
+
+	result, err := client.GenerateFromPromptTemplate(
+		context.Background(),
+		templateID,
+		map[string]string{"language": "French", "text": "Hello"},
+		wx.WithMaxNewTokens(20),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Text != "Bonjour" {
+		t.Errorf("Expected the generated text, got %q", result.Text)
+	}
+
+	expectedPath := fmt.Sprintf(wx.DeploymentGenerateTextEndpointFormat, templateID)
+	if path != expectedPath {
+		t.Errorf("Expected path %s, got %s", expectedPath, path)
+	}
+
+	if _, found := payload["input"]; found {
+		t.Error("Expected no input in the body, the prompt is stored in the template")
+	}
+
+	parameters, _ := payload["parameters"].(map[string]interface{})
+	expectedVars := map[string]interface{}{"language": "French", "text": "Hello"}
+	if !reflect.DeepEqual(parameters["prompt_variables"], expectedVars) {
+		t.Errorf("Expected prompt variables %v, got %v", expectedVars, parameters["prompt_variables"])
+	}
+	if parameters["max_new_tokens"] != float64(20) {
+		t.Errorf("Expected the generation parameters next to the variables, got %v", parameters)
+	}
+}
+
+func TestDeploymentGenerationTimeLimitFromContext(t *testing.T) {
+	var payload struct {
+		Parameters struct {
+			TimeLimit *uint `json:"time_limit"`
+		} `json:"parameters"`
+	}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		payload.Parameters.TimeLimit = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	generations := map[string]func(options ...wx.GenerateOption) error{
+		"deployment": func(options ...wx.GenerateOption) error {
+			_, err := client.GenerateFromDeployment(ctx, "my-deployment", wx.GenerateTextRequest{Prompt: "Hello", Options: options})
+			return err
+		},
+		"prompt template": func(options ...wx.GenerateOption) error {
+			_, err := client.GenerateFromPromptTemplate(ctx, "my-template", nil, options...)
+			return err
+		},
+	}
+
+	for name, generate := range generations {
+		if err := generate(); err != nil {
+			t.Fatalf("Expected no error from the %s, got %v", name, err)
+		}
+		if timeLimit := payload.Parameters.TimeLimit; timeLimit == nil || *timeLimit > 10000 || *timeLimit < 9000 {
+			t.Errorf("Expected a time limit of about 10000ms from the deadline for the %s, got %v", name, timeLimit)
+		}
+
+		if err := generate(wx.WithTimeLimit(2500)); err != nil {
+			t.Fatalf("Expected no error from the %s, got %v", name, err)
+		}
+		if timeLimit := payload.Parameters.TimeLimit; timeLimit == nil || *timeLimit != 2500 {
+			t.Errorf("Expected the explicit time limit to be kept for the %s, got %v", name, timeLimit)
+		}
+	}
+}
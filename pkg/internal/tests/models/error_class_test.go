@@ -0,0 +1,84 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected wx.ErrorClass
+	}{
+		{"rate limited", &wx.WatsonxError{StatusCode: http.StatusTooManyRequests}, wx.ErrorClassRateLimited},
+		{"unauthorized", &wx.WatsonxError{StatusCode: http.StatusUnauthorized}, wx.ErrorClassAuth},
+		{"forbidden", &wx.WatsonxError{StatusCode: http.StatusForbidden}, wx.ErrorClassAuth},
+		{"bad request", &wx.WatsonxError{StatusCode: http.StatusBadRequest}, wx.ErrorClassClient},
+		{"not found", &wx.WatsonxError{StatusCode: http.StatusNotFound}, wx.ErrorClassClient},
+		{"service unavailable", &wx.WatsonxError{StatusCode: http.StatusServiceUnavailable}, wx.ErrorClassTransient},
+		{"gateway timeout", &wx.WatsonxError{StatusCode: http.StatusGatewayTimeout}, wx.ErrorClassTransient},
+		{"internal server error", &wx.WatsonxError{StatusCode: http.StatusInternalServerError}, wx.ErrorClassServer},
+		{"wrapped watsonx error", fmt.Errorf("chat failed: %w", &wx.WatsonxError{StatusCode: http.StatusBadGateway}), wx.ErrorClassTransient},
+		{"invalid response", &wx.InvalidResponseError{StatusCode: http.StatusOK, Err: errors.New("empty results")}, wx.ErrorClassTransient},
+		{"connection refused", &url.Error{Op: "Post", URL: "https://watsonx", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, wx.ErrorClassNetwork},
+		{"unexpected EOF", fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF), wx.ErrorClassNetwork},
+		{"DNS failure", &net.DNSError{Err: "no such host", Name: "watsonx.invalid", IsNotFound: true}, wx.ErrorClassUnknown},
+		{"cancelled", context.Canceled, wx.ErrorClassUnknown},
+		{"deadline", &wx.RetryTimeoutError{Err: context.DeadlineExceeded}, wx.ErrorClassUnknown},
+		{"request error", &wx.RequestError{Op: "encode payload", Err: errors.New("unsupported value")}, wx.ErrorClassClient},
+		{"unknown", errors.New("something else"), wx.ErrorClassUnknown},
+		{"nil", nil, wx.ErrorClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wx.ClassifyError(tt.err); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestDefaultRetryIfUsesErrorClass validates that only transient, rate limited, network and server errors are retried by default
+func TestDefaultRetryIfUsesErrorClass(t *testing.T) {
+	tests := []struct {
+		err   error
+		retry bool
+	}{
+		{&wx.WatsonxError{StatusCode: http.StatusServiceUnavailable}, true},
+		{&wx.WatsonxError{StatusCode: http.StatusTooManyRequests}, true},
+		{&wx.WatsonxError{StatusCode: http.StatusInternalServerError}, true},
+		{&net.OpError{Op: "dial", Err: syscall.ECONNRESET}, true},
+		{&wx.WatsonxError{StatusCode: http.StatusBadRequest}, false},
+		{&wx.WatsonxError{StatusCode: http.StatusUnauthorized}, false},
+		{errors.New("something else"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(wx.ClassifyError(tt.err).String(), func(t *testing.T) {
+			attempts := 0
+			wx.Retry(
+				func() (*http.Response, error) {
+					attempts++
+					return nil, tt.err
+				},
+				wx.WithRetries(2),
+				wx.WithTimer(&recordingTimer{}),
+			)
+
+			if retried := attempts > 1; retried != tt.retry {
+				t.Errorf("Expected retried to be %v for %v, got %d attempts", tt.retry, tt.err, attempts)
+			}
+		})
+	}
+}
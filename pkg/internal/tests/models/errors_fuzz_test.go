@@ -0,0 +1,77 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func FuzzDecodeWatsonxError(f *testing.F) {
+	f.Add(400, []byte(`{"errors":[{"code":"invalid_input","message":"bad prompt"}],"trace":"abc","status_code":400}`), "")
+	f.Add(429, []byte(`{"errors":[{"code":"token_quota_reached","message":"quota"}]}`), "5")
+	f.Add(503, []byte(`<html>Service Unavailable</html>`), "Wed, 21 Oct 2015 07:28:00 GMT")
+	f.Add(500, []byte(``), "-1")
+	f.Add(401, []byte(`{"errors":null}`), "9223372036854775807")
+	f.Add(0, []byte(`{"errors":[{}]}`), "")
+	f.Add(-1, []byte(strings.Repeat("[", 100000)), "")
+	f.Add(400, []byte(`{"errors":[`+strings.Repeat(`{"code":"x","message":"y"},`, 1000)+`{}]}`), "")
+
+	f.Fuzz(func(t *testing.T, statusCode int, body []byte, retryAfter string) {
+		resp := &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{"Retry-After": {retryAfter}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}
+
+		err := wx.DecodeWatsonxError(resp)
+
+		var wxErr *wx.WatsonxError
+		if !errors.As(err, &wxErr) {
+			t.Fatalf("Expected a *WatsonxError, got %T", err)
+		}
+		if wxErr.StatusCode != statusCode {
+			t.Errorf("Expected status %d, got %d", statusCode, wxErr.StatusCode)
+		}
+		if wxErr.RetryAfter < 0 {
+			t.Errorf("Expected a non-negative Retry-After, got %v", wxErr.RetryAfter)
+		}
+
+		// None of the accessors may panic on whatever was decoded
+		_ = err.Error()
+		_ = wxErr.Suggestion()
+		_ = wx.ClassifyError(err)
+		_ = errors.Is(err, wx.ErrQuotaExceeded)
+		_ = errors.Is(err, wx.ErrTokenExpired)
+
+		// The body stays readable after decoding, up to the read limit
+		restored, readErr := io.ReadAll(resp.Body)
+		if readErr != nil || int64(len(body)) <= wx.DefaultMaxErrorBodySize && !bytes.Equal(restored, body) {
+			t.Errorf("Expected the body to be restored, got %q (%v)", restored, readErr)
+		}
+	})
+}
+
+// TestDecodeWatsonxErrorCrashers keeps the inputs found by FuzzDecodeWatsonxError as regression tests
+func TestDecodeWatsonxErrorCrashers(t *testing.T) {
+	// A Retry-After too large for a time.Duration used to overflow into a negative delay
+	err := wx.DecodeWatsonxError(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"9223372036854775807"}},
+		Body:       http.NoBody,
+	})
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) || wxErr.RetryAfter <= 0 {
+		t.Errorf("Expected a saturated Retry-After, got %v", err)
+	}
+
+	// A response without a body used to panic
+	err = wx.DecodeWatsonxError(&http.Response{StatusCode: http.StatusBadGateway})
+	if !errors.As(err, &wxErr) || wxErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected a status-only error, got %v", err)
+	}
+}
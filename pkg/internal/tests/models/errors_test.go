@@ -0,0 +1,250 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestWatsonxErrorQuotaAndPlanLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       *wx.WatsonxError
+		quota     bool
+		planLimit bool
+	}{
+		{
+			name:  "token quota reached",
+			err:   &wx.WatsonxError{StatusCode: http.StatusForbidden, Errors: []wx.ErrorDetail{{Code: "token_quota_reached", Message: "The token quota has been reached"}}},
+			quota: true,
+		},
+		{
+			name:  "authorization rejected",
+			err:   &wx.WatsonxError{StatusCode: http.StatusForbidden, Errors: []wx.ErrorDetail{{Code: "authorization_rejected", Message: "Quota exceeded"}}},
+			quota: true,
+		},
+		{
+			name: "authorization rejected on authentication",
+			err:  &wx.WatsonxError{StatusCode: http.StatusUnauthorized, Errors: []wx.ErrorDetail{{Code: "authorization_rejected", Message: "Invalid credentials"}}},
+		},
+		{
+			name:      "plan limit code",
+			err:       &wx.WatsonxError{StatusCode: http.StatusForbidden, Errors: []wx.ErrorDetail{{Code: "plan_limit_reached", Message: "Limit reached"}}},
+			planLimit: true,
+		},
+		{
+			name:      "plan limit message",
+			err:       &wx.WatsonxError{StatusCode: http.StatusForbidden, Errors: []wx.ErrorDetail{{Code: "authorization_rejected", Message: "The Lite plan limit for this instance has been reached"}}},
+			planLimit: true,
+		},
+		{
+			name: "generic forbidden",
+			err:  &wx.WatsonxError{StatusCode: http.StatusForbidden, Errors: []wx.ErrorDetail{{Code: "forbidden", Message: "Access denied"}}},
+		},
+		{
+			name: "bad request",
+			err:  &wx.WatsonxError{StatusCode: http.StatusBadRequest, Errors: []wx.ErrorDetail{{Code: "invalid_input", Message: "Bad input"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// wrap to make sure the sentinels are found through the error chain
+			err := fmt.Errorf("generation failed: %w", tt.err)
+
+			if got := errors.Is(err, wx.ErrQuotaExceeded); got != tt.quota {
+				t.Errorf("Expected errors.Is(err, ErrQuotaExceeded) to be %v, got %v", tt.quota, got)
+			}
+
+			if got := errors.Is(err, wx.ErrPlanLimit); got != tt.planLimit {
+				t.Errorf("Expected errors.Is(err, ErrPlanLimit) to be %v, got %v", tt.planLimit, got)
+			}
+		})
+	}
+}
+
+func TestWatsonxErrorTokenExpiredAndForbidden(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		body         string
+		tokenExpired bool
+		forbidden    bool
+	}{
+		{
+			name:         "expired token",
+			statusCode:   http.StatusUnauthorized,
+			body:         `{"errors":[{"code":"authentication_token_expired","message":"Failed to authenticate the request due to an expired token"}]}`,
+			tokenExpired: true,
+		},
+		{
+			name:         "invalid token",
+			statusCode:   http.StatusUnauthorized,
+			body:         `{"errors":[{"code":"authentication_token_not_valid","message":"Failed to authenticate the request due to invalid token"}]}`,
+			tokenExpired: true,
+		},
+		{
+			name:       "missing project permission",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"errors":[{"code":"user_not_authorized","message":"User does not have permission to access the project"}]}`,
+			forbidden:  true,
+		},
+		{
+			name:       "no service instance",
+			statusCode: http.StatusForbidden,
+			body:       `{"errors":[{"code":"no_associated_service_instance_error","message":"No associated WML instance"}]}`,
+			forbidden:  true,
+		},
+		{
+			name:       "unauthorized without code",
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.statusCode,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+			err := fmt.Errorf("chat failed: %w", wx.DecodeWatsonxError(resp))
+
+			if got := errors.Is(err, wx.ErrTokenExpired); got != tt.tokenExpired {
+				t.Errorf("Expected errors.Is(err, ErrTokenExpired) to be %v, got %v", tt.tokenExpired, got)
+			}
+
+			if got := errors.Is(err, wx.ErrForbidden); got != tt.forbidden {
+				t.Errorf("Expected errors.Is(err, ErrForbidden) to be %v, got %v", tt.forbidden, got)
+			}
+		})
+	}
+}
+
+// TestDecodeWatsonxErrorCapsBodyRead verifies that a huge error body is read only up to the cap.
+func TestDecodeWatsonxErrorCapsBodyRead(t *testing.T) {
+	source := &countingReader{r: strings.NewReader(strings.Repeat("x", 10<<20))}
+
+	resp := &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Header:     http.Header{},
+		Body:       io.NopCloser(source),
+	}
+
+	err := wx.DecodeWatsonxError(resp)
+
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) {
+		t.Fatalf("Expected WatsonxError, got %v", err)
+	}
+
+	if int64(source.read) > wx.DefaultMaxErrorBodySize+1 {
+		t.Errorf("Expected at most %d bytes read, got %d", wx.DefaultMaxErrorBodySize+1, source.read)
+	}
+
+	if wxErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, wxErr.StatusCode)
+	}
+
+	if !wxErr.Truncated || !strings.Contains(wxErr.Error(), "truncated") {
+		t.Errorf("Expected the error to note the truncation, got %q", wxErr.Error())
+	}
+}
+
+// TestRetryMaxErrorBodySize verifies that the retry loop honors a custom error body cap.
+func TestRetryMaxErrorBodySize(t *testing.T) {
+	body := `{"errors":[{"code":"internal_error","message":"boom"}],"trace":"abc"}`
+	source := &countingReader{r: strings.NewReader(body + strings.Repeat(" ", 1024))}
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Header:     http.Header{},
+				Body:       io.NopCloser(source),
+			}, nil
+		},
+		wx.WithRetries(1),
+		wx.WithMaxErrorBodySize(int64(len(body))),
+	)
+
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) {
+		t.Fatalf("Expected WatsonxError, got %v", err)
+	}
+
+	if source.read > len(body)+1 {
+		t.Errorf("Expected at most %d bytes read, got %d", len(body)+1, source.read)
+	}
+
+	if !wxErr.Truncated {
+		t.Errorf("Expected the error to be marked as truncated")
+	}
+
+	if len(wxErr.Errors) != 1 || wxErr.Errors[0].Code != "internal_error" {
+		t.Errorf("Expected the error details to be parsed, got %+v", wxErr.Errors)
+	}
+}
+
+func TestWatsonxErrorSuggestion(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        *wx.WatsonxError
+		suggestion string
+	}{
+		{
+			name:       "unauthorized",
+			err:        &wx.WatsonxError{StatusCode: http.StatusUnauthorized},
+			suggestion: "Refresh your IAM token or check that your API key is valid",
+		},
+		{
+			name: "context length",
+			err: &wx.WatsonxError{StatusCode: http.StatusBadRequest, Errors: []wx.ErrorDetail{{
+				Code:    "invalid_input_argument",
+				Message: "the number of input tokens plus max_new_tokens exceeds the model context length",
+			}}},
+			suggestion: "Reduce max_new_tokens or shorten the prompt to fit the model's context length",
+		},
+		{
+			name:       "quota exceeded",
+			err:        &wx.WatsonxError{StatusCode: http.StatusForbidden, Errors: []wx.ErrorDetail{{Code: "token_quota_reached"}}},
+			suggestion: "Wait for your token quota to reset or increase it",
+		},
+		{
+			name:       "rate limited",
+			err:        &wx.WatsonxError{StatusCode: http.StatusTooManyRequests},
+			suggestion: "Reduce the request rate or retry after a delay",
+		},
+		{
+			name: "unsupported model with documentation link",
+			err: &wx.WatsonxError{StatusCode: http.StatusNotFound, Errors: []wx.ErrorDetail{{
+				Code:     "model_not_supported",
+				MoreInfo: "https://cloud.ibm.com/apidocs/watsonx-ai",
+			}}},
+			suggestion: "Check the model ID; list the available models with the foundation model specs endpoint. See https://cloud.ibm.com/apidocs/watsonx-ai",
+		},
+		{
+			name:       "server error",
+			err:        &wx.WatsonxError{StatusCode: http.StatusBadGateway},
+			suggestion: "The watsonx service is unavailable; retry later",
+		},
+		{
+			name:       "no hint",
+			err:        &wx.WatsonxError{StatusCode: http.StatusConflict},
+			suggestion: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Suggestion(); got != tt.suggestion {
+				t.Errorf("Expected suggestion %q, got %q", tt.suggestion, got)
+			}
+		})
+	}
+}
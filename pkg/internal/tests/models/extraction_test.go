@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestExtractTextAndWait(t *testing.T) {
+	var polls int32
+	var payload wx.ExtractionPayload
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		status := wx.ExtractionSubmitted
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == wx.TextExtractionEndpoint:
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == wx.TextExtractionEndpoint+"/job-1":
+			if r.URL.Query().Get("project_id") != "mock-project-id" {
+				t.Errorf("Expected project_id in the query, got %s", r.URL.RawQuery)
+			}
+			switch atomic.AddInt32(&polls, 1) {
+			case 1:
+				status = wx.ExtractionRunning
+			default:
+				status = wx.ExtractionCompleted
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"metadata": map[string]interface{}{"id": "job-1", "created_at": "2024-10-01T00:00:00Z"},
+			"entity": map[string]interface{}{
+				"results": map[string]interface{}{"status": status, "number_pages_processed": 3},
+			},
+		})
+	})
+
+	job, err := client.ExtractText(context.Background(), wx.ExtractionRequest{
+		Document:      wx.COSReference{ConnectionID: "cos-connection", Bucket: "documents", FileName: "report.pdf"},
+		Results:       wx.COSReference{ConnectionID: "cos-connection", Bucket: "documents", FileName: "report.md"},
+		OutputFormats: []string{"md"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if job.ID != "job-1" || job.Status != wx.ExtractionSubmitted {
+		t.Errorf("Expected a submitted job-1, got %+v", job)
+	}
+
+	if payload.DocumentReference.FileName != "report.pdf" || payload.Parameters == nil || payload.Parameters.RequestedOutputs[0] != "md" {
+		t.Errorf("Expected the document and output format in the request, got %+v", payload)
+	}
+
+	result, err := client.WaitForExtraction(context.Background(), job.ID, wx.WithBackoff(time.Millisecond), wx.WithNoJitter())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Status != wx.ExtractionCompleted || result.PagesProcessed != 3 {
+		t.Errorf("Expected a completed job with 3 pages, got %+v", result)
+	}
+
+	if got := atomic.LoadInt32(&polls); got != 2 {
+		t.Errorf("Expected 2 polls, got %d", got)
+	}
+}
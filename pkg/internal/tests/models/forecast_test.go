@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestForecast(t *testing.T) {
+	var path string
+	var payload map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{
+			"model_id": "ibm/granite-ttm-512-96-r2",
+			"created_at": "2024-10-01T00:00:00.000Z",
+			"results": [{
+				"date": ["2024-01-04T00:00:00", "2024-01-05T00:00:00"],
+				"sales": [14.5, 15.25]
+			}],
+			"input_data_points": 3,
+			"output_data_points": 2
+		}`))
+	})
+
+	response, err := client.Forecast(context.Background(), wx.ForecastRequest{
+		Model: "ibm/granite-ttm-512-96-r2",
+		Data: map[string]interface{}{
+			"date":  []string{"2024-01-01T00:00:00", "2024-01-02T00:00:00", "2024-01-03T00:00:00"},
+			"sales": []float64{11, 12.5, 13.75},
+		},
+		Schema: wx.ForecastSchema{
+			TimestampColumn: "date",
+			TargetColumns:   []string{"sales"},
+			Freq:            "1D",
+		},
+		PredictionLength: 2,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if path != wx.ForecastEndpoint {
+		t.Errorf("Expected path %s, got %s", wx.ForecastEndpoint, path)
+	}
+
+	schema, _ := payload["schema"].(map[string]interface{})
+	if schema["timestamp_column"] != "date" {
+		t.Errorf("Expected schema in request, got %v", payload["schema"])
+	}
+
+	parameters, _ := payload["parameters"].(map[string]interface{})
+	if parameters["prediction_length"] != float64(2) {
+		t.Errorf("Expected prediction length in request, got %v", payload["parameters"])
+	}
+
+	expected := []wx.ForecastPoint{
+		{Timestamp: "2024-01-04T00:00:00", Values: map[string]float64{"sales": 14.5}},
+		{Timestamp: "2024-01-05T00:00:00", Values: map[string]float64{"sales": 15.25}},
+	}
+
+	if len(response.Points) != len(expected) {
+		t.Fatalf("Expected %d points, got %v", len(expected), response.Points)
+	}
+
+	for i, point := range expected {
+		got := response.Points[i]
+		if got.Timestamp != point.Timestamp || got.Values["sales"] != point.Values["sales"] {
+			t.Errorf("Expected point %d to be %+v, got %+v", i, point, got)
+		}
+	}
+
+	if response.OutputDataPoints != 2 {
+		t.Errorf("Expected 2 output data points, got %d", response.OutputDataPoints)
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// newGenerateJSONClient creates a mock client that answers generations with text and records the request payload
+func newGenerateJSONClient(t *testing.T, text string, payload *map[string]interface{}) *wx.Client {
+	return newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(payload)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{{"generated_text": text}},
+		})
+	})
+}
+
+func TestGenerateJSON(t *testing.T) {
+	var payload map[string]interface{}
+	client := newGenerateJSONClient(t, `{"capital": "Paris", "population": 2102650}`, &payload)
+
+	var out struct {
+		Capital    string `json:"capital"`
+		Population int    `json:"population"`
+	}
+
+	err := client.GenerateJSON(context.Background(), wx.GenerateTextRequest{
+		Model:  "mock-model",
+		Prompt: "What is the capital of France?",
+	}, &out)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if out.Capital != "Paris" || out.Population != 2102650 {
+		t.Errorf("Expected decoded output, got %+v", out)
+	}
+
+	parameters, _ := payload["parameters"].(map[string]interface{})
+	responseFormat, _ := parameters["response_format"].(map[string]interface{})
+	if responseFormat["type"] != "json_object" {
+		t.Errorf("Expected json_object response format in request, got %v", parameters["response_format"])
+	}
+}
+
+func TestGenerateJSONWithSchema(t *testing.T) {
+	var payload map[string]interface{}
+	client := newGenerateJSONClient(t, `{"answer": 4}`, &payload)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"answer": map[string]interface{}{"type": "integer"}},
+	}
+
+	var out map[string]int
+	err := client.GenerateJSON(context.Background(), wx.GenerateTextRequest{
+		Model:   "mock-model",
+		Prompt:  "What is 2+2?",
+		Options: []wx.GenerateOption{wx.WithJSONSchema(schema)},
+	}, &out)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	parameters, _ := payload["parameters"].(map[string]interface{})
+	responseFormat, _ := parameters["response_format"].(map[string]interface{})
+	if responseFormat["type"] != "json_schema" || responseFormat["json_schema"] == nil {
+		t.Errorf("Expected json_schema response format in request, got %v", parameters["response_format"])
+	}
+}
+
+func TestGenerateJSONInvalidOutput(t *testing.T) {
+	var payload map[string]interface{}
+	client := newGenerateJSONClient(t, `The capital is Paris`, &payload)
+
+	var out map[string]string
+	err := client.GenerateJSON(context.Background(), wx.GenerateTextRequest{
+		Model:  "mock-model",
+		Prompt: "What is the capital of France?",
+	}, &out)
+
+	var decodeErr *wx.JSONDecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected JSONDecodeError, got %v", err)
+	}
+
+	if decodeErr.Text != "The capital is Paris" {
+		t.Errorf("Expected raw text to be attached, got %q", decodeErr.Text)
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected the underlying JSON syntax error, got %v", decodeErr.Err)
+	}
+}
+
+type capital struct {
+	Capital string `json:"capital"`
+}
+
+func TestGenerateJSONWithPromptsAndCodec(t *testing.T) {
+	codec := &recordingCodec{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"generated_text":"{\"capital\":\"Paris\"}"},{"generated_text":"{\"capital\":\"Rome\"}"}]}`))
+	}, wx.WithCodec(codec))
+
+	var out []capital
+	err := client.GenerateJSON(context.Background(), wx.GenerateTextRequest{
+		Model:   "mock-model",
+		Prompts: []string{"Capital of France?", "Capital of Italy?"},
+	}, &out)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(out) != 2 || out[0].Capital != "Paris" || out[1].Capital != "Rome" {
+		t.Errorf("Expected one output per prompt, got %+v", out)
+	}
+
+	codec.mu.Lock()
+	defer codec.mu.Unlock()
+	if last := codec.unmarshaled[len(codec.unmarshaled)-1]; last != "*[]test.capital" {
+		t.Errorf("Expected the output to be decoded with the client codec, got %s", last)
+	}
+}
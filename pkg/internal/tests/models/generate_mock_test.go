@@ -0,0 +1,497 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateMultipleResults(t *testing.T) {
+	var payload map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{
+			"model_id": "mock-model",
+			"created_at": "2024-06-01T12:00:00.000Z",
+			"results": [
+				{"generated_text": "first", "stop_reason": "eos_token"},
+				{"generated_text": "second", "stop_reason": "eos_token"},
+				{"generated_text": "third", "stop_reason": "max_tokens"}
+			]
+		}`))
+	})
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:   "mock-model",
+		Prompt:  "Give me a name",
+		Options: []wx.GenerateOption{wx.WithNumReturnSequences(3)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	parameters, _ := payload["parameters"].(map[string]interface{})
+	if parameters["num_return_sequences"] != float64(3) {
+		t.Errorf("Expected num_return_sequences in request, got %v", payload["parameters"])
+	}
+
+	if len(response.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(response.Results))
+	}
+
+	for i, expected := range []string{"first", "second", "third"} {
+		if response.Results[i].Text != expected {
+			t.Errorf("Expected result %d to be %q, got %q", i, expected, response.Results[i].Text)
+		}
+	}
+
+	if response.FirstText() != "first" {
+		t.Errorf("Expected FirstText to be %q, got %q", "first", response.FirstText())
+	}
+
+	if response.ModelID != "mock-model" || response.CreatedAt.IsZero() {
+		t.Errorf("Expected model ID and creation time, got %q and %v", response.ModelID, response.CreatedAt)
+	}
+}
+
+func TestGenerateTextResponseFirstTextEmpty(t *testing.T) {
+	if text := (wx.GenerateTextResponse{}).FirstText(); text != "" {
+		t.Errorf("Expected empty text without results, got %q", text)
+	}
+}
+
+func TestGenerateLargeTokenCounts(t *testing.T) {
+	const large int64 = math.MaxInt32 + 12345
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"results":[{"generated_text":"ok","generated_token_count":%d,"input_token_count":%d}]}`, large, large+1)
+	})
+
+	result, err := client.GenerateText("model", "prompt")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.GeneratedTokenCount != large {
+		t.Errorf("Expected generated token count %d, got %d", large, result.GeneratedTokenCount)
+	}
+
+	if result.InputTokenCount != large+1 {
+		t.Errorf("Expected input token count %d, got %d", large+1, result.InputTokenCount)
+	}
+}
+
+func TestGenerateStopSequenceReason(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"generated_text":"Answer: 42\n\n","stop_reason":"stop_sequence"}]}`))
+	})
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:   "model",
+		Prompt:  "prompt",
+		Options: []wx.GenerateOption{wx.WithStopSequences([]string{"END", "\n\n"})},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result := response.Results[0]
+	if result.StopReason != wx.StopSequence {
+		t.Errorf("Expected stop reason %s, got %s", wx.StopSequence, result.StopReason)
+	}
+
+	if result.MatchedStopSequence != "\n\n" {
+		t.Errorf("Expected matched stop sequence %q, got %q", "\n\n", result.MatchedStopSequence)
+	}
+}
+
+func TestGenerateTokenLogProbs(t *testing.T) {
+	var payload map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"results":[{
+			"generated_text":"Hello world",
+			"generated_tokens":[
+				{"text":"Hello","logprob":-0.25,"rank":1,"top_tokens":[{"text":"Hello","logprob":-0.25},{"text":"Hi","logprob":-1.5}]},
+				{"text":" world","logprob":-0.75,"rank":2,"top_tokens":[{"text":" there","logprob":-0.5},{"text":" world","logprob":-0.75}]}
+			]
+		}]}`))
+	})
+
+	result, err := client.GenerateText("model", "prompt", wx.WithTokenLogProbs(2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	parameters, _ := payload["parameters"].(map[string]interface{})
+	returnOptions, _ := parameters["return_options"].(map[string]interface{})
+	if returnOptions["token_logprobs"] != true || returnOptions["generated_tokens"] != true || returnOptions["top_n_tokens"] != float64(2) {
+		t.Errorf("Expected token log probabilities to be requested, got %v", parameters["return_options"])
+	}
+
+	if len(result.GeneratedTokens) != 2 {
+		t.Fatalf("Expected 2 generated tokens, got %d", len(result.GeneratedTokens))
+	}
+
+	text := ""
+	for _, token := range result.GeneratedTokens {
+		text += token.Text
+	}
+	if text != result.Text {
+		t.Errorf("Expected the tokens to align with %q, got %q", result.Text, text)
+	}
+
+	second := result.GeneratedTokens[1]
+	if second.LogProb == nil || *second.LogProb != -0.75 || second.Rank == nil || *second.Rank != 2 {
+		t.Errorf("Expected logprob -0.75 and rank 2, got %+v", second)
+	}
+
+	if len(second.TopTokens) != 2 || second.TopTokens[0].Text != " there" {
+		t.Errorf("Expected the top tokens to be parsed, got %+v", second.TopTokens)
+	}
+}
+
+func TestGenerateRawResponse(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"model_id": "mock-model",
+			"results": [{"generated_text": "hello", "stop_reason": "eos_token"}],
+			"system": {"warnings": [{"message": "model is deprecated"}]},
+			"billing": {"units": 7}
+		}`))
+	})
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:  "mock-model",
+		Prompt: "Say hello",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.FirstText() != "hello" || response.ModelID != "mock-model" {
+		t.Errorf("Expected known fields to be parsed, got %+v", response)
+	}
+
+	var extra struct {
+		Billing struct {
+			Units int `json:"units"`
+		} `json:"billing"`
+	}
+	if err := json.Unmarshal(response.Raw, &extra); err != nil {
+		t.Fatalf("Expected the raw payload to be valid JSON, got %v", err)
+	}
+	if extra.Billing.Units != 7 {
+		t.Errorf("Expected the unmapped field to be accessible through Raw, got %s", response.Raw)
+	}
+}
+
+func TestChatRawResponse(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "chat-1",
+			"model_id": "mock-model",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "hi"}, "finish_reason": "stop"}],
+			"service_tier": "premium"
+		}`))
+	})
+
+	response, err := client.Chat("mock-model", []wx.ChatMessage{wx.CreateUserMessage("hello")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.ID != "chat-1" || len(response.Choices) != 1 {
+		t.Errorf("Expected known fields to be parsed, got %+v", response)
+	}
+
+	var extra map[string]interface{}
+	if err := json.Unmarshal(response.Raw, &extra); err != nil {
+		t.Fatalf("Expected the raw payload to be valid JSON, got %v", err)
+	}
+	if extra["service_tier"] != "premium" {
+		t.Errorf("Expected the unmapped field to be accessible through Raw, got %s", response.Raw)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	var payload wx.GenerateTextPayload
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "I am a model.", "stop_reason": "eos_token"}]}`))
+	})
+
+	text, err := client.Complete(
+		context.Background(),
+		"mock-model",
+		"Who are you?",
+		wx.WithMaxNewTokens(64),
+		wx.WithTemperature(0.3),
+		wx.WithStopSequences([]string{"\n"}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if text != "I am a model." {
+		t.Errorf("Expected the generated text, got %q", text)
+	}
+
+	if payload.Model != "mock-model" || payload.Prompt != "Who are you?" {
+		t.Errorf("Expected the model and prompt in the request, got %q and %q", payload.Model, payload.Prompt)
+	}
+
+	params := payload.Parameters
+	if params == nil || params.MaxNewTokens == nil || *params.MaxNewTokens != 64 ||
+		params.Temperature == nil || *params.Temperature != 0.3 ||
+		params.StopSequences == nil || len(*params.StopSequences) != 1 {
+		t.Errorf("Expected the options in the request parameters, got %v", params)
+	}
+}
+
+func TestGenerateMultiplePrompts(t *testing.T) {
+	prompts := []string{"Capital of France?", "Capital of Japan?", "Capital of Peru?"}
+	answers := map[string]string{
+		"Capital of France?": "Paris",
+		"Capital of Japan?":  "Tokyo",
+		"Capital of Peru?":   "Lima",
+	}
+
+	var raw map[string]interface{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload wx.GenerateTextPayload
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &raw)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+
+		results := []map[string]string{}
+		for _, prompt := range payload.Prompts {
+			results = append(results, map[string]string{"generated_text": answers[prompt], "stop_reason": "eos_token"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"model_id": "mock-model", "results": results})
+	})
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:   "mock-model",
+		Prompts: prompts,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	input, ok := raw["input"].([]interface{})
+	if !ok || len(input) != 3 {
+		t.Fatalf("Expected the prompts as an array input, got %v", raw["input"])
+	}
+
+	if len(response.Results) != len(prompts) {
+		t.Fatalf("Expected %d results, got %d", len(prompts), len(response.Results))
+	}
+
+	for i, prompt := range prompts {
+		if response.Results[i].Text != answers[prompt] {
+			t.Errorf("Expected result %d for %q to be %q, got %q", i, prompt, answers[prompt], response.Results[i].Text)
+		}
+	}
+}
+
+func TestGenerateMultiplePromptsMissingResult(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "Paris"}]}`))
+	})
+
+	_, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:   "mock-model",
+		Prompts: []string{"Capital of France?", "Capital of Japan?"},
+	})
+	if err == nil {
+		t.Error("Expected an error when the results do not match the prompts")
+	}
+
+	_, err = client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:   "mock-model",
+		Prompt:  "Capital of France?",
+		Prompts: []string{"Capital of Japan?"},
+	})
+	if err == nil {
+		t.Error("Expected an error when both a prompt and prompts are set")
+	}
+}
+
+func TestGenerateTimeLimitFromContext(t *testing.T) {
+	var payload wx.GenerateTextPayload
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello"}]}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := client.Complete(ctx, "mock-model", "Say hello"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	timeLimit := payload.Parameters.TimeLimit
+	if timeLimit == nil || *timeLimit > 10000 || *timeLimit < 9000 {
+		t.Errorf("Expected a time limit of about 10000ms from the deadline, got %v", timeLimit)
+	}
+
+	if _, err := client.Complete(ctx, "mock-model", "Say hello", wx.WithTimeLimit(2500)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if timeLimit := payload.Parameters.TimeLimit; timeLimit == nil || *timeLimit != 2500 {
+		t.Errorf("Expected the explicit time limit to be kept, got %v", timeLimit)
+	}
+
+	if _, err := client.Complete(context.Background(), "mock-model", "Say hello"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if timeLimit := payload.Parameters.TimeLimit; timeLimit != nil {
+		t.Errorf("Expected no time limit without a deadline, got %v", *timeLimit)
+	}
+}
+
+func TestGenerateIncludeStopSequence(t *testing.T) {
+	for _, include := range []bool{false, true} {
+		t.Run(fmt.Sprintf("include=%v", include), func(t *testing.T) {
+			var raw map[string]interface{}
+			client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&raw)
+				w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello", "stop_reason": "stop_sequence"}]}`))
+			})
+
+			_, err := client.Complete(
+				context.Background(),
+				"mock-model",
+				"Say hello",
+				wx.WithStopSequences([]string{"."}),
+				wx.WithIncludeStopSequence(include),
+			)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			parameters, _ := raw["parameters"].(map[string]interface{})
+			value, ok := parameters["include_stop_sequence"]
+			if !ok || value != include {
+				t.Errorf("Expected include_stop_sequence to be %v in the request, got %v", include, parameters)
+			}
+		})
+	}
+}
+
+func TestGenerateInputText(t *testing.T) {
+	var raw map[string]interface{}
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&raw)
+		w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello", "input_text": "<s>[INST] Say hello [/INST]", "stop_reason": "eos_token"}]}`))
+	})
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:   "mock-model",
+		Prompt:  "Say hello",
+		Options: []wx.GenerateOption{wx.WithInputText()},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	parameters, _ := raw["parameters"].(map[string]interface{})
+	returnOptions, _ := parameters["return_options"].(map[string]interface{})
+	if returnOptions["input_text"] != true {
+		t.Errorf("Expected input_text to be requested, got %v", parameters)
+	}
+
+	if got := response.Results[0].InputText; got != "<s>[INST] Say hello [/INST]" {
+		t.Errorf("Expected the input text echo to be parsed, got %q", got)
+	}
+}
+
+func TestGenerateSystemWarnings(t *testing.T) {
+	var logs bytes.Buffer
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"model_id": "mock-model",
+			"results": [{"generated_text": "hello", "stop_reason": "max_tokens"}],
+			"system": {"warnings": [{
+				"message": "The input was truncated to 4096 tokens",
+				"id": "input_truncated",
+				"more_info": "https://cloud.ibm.com/apidocs/watsonx-ai"
+			}]}
+		}`))
+	}, wx.WithWarningLogger(log.New(&logs, "", 0)))
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{
+		Model:  "mock-model",
+		Prompt: "Say hello",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []wx.Warning{{
+		Message:  "The input was truncated to 4096 tokens",
+		ID:       "input_truncated",
+		MoreInfo: "https://cloud.ibm.com/apidocs/watsonx-ai",
+	}}
+	if response.System == nil || !reflect.DeepEqual(response.System.Warnings, expected) {
+		t.Fatalf("Expected the truncation warning to be parsed, got %+v", response.System)
+	}
+
+	if !strings.Contains(logs.String(), "input_truncated") {
+		t.Errorf("Expected the warning to be logged, got %q", logs.String())
+	}
+}
+
+func TestGenerateRequestError(t *testing.T) {
+	var calls int32
+	client := newMockClientWithHttpOptions(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"results": [{"generated_text": "hello"}]}`))
+	}, []wx.HttpClientOption{wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(0), wx.WithNoJitter())})
+
+	// NaN cannot be encoded in JSON, so the payload fails to marshal
+	_, err := client.Complete(context.Background(), "mock-model", "Say hello", wx.WithTemperature(math.NaN()))
+
+	var requestErr *wx.RequestError
+	if !errors.As(err, &requestErr) {
+		t.Fatalf("Expected a *RequestError, got %T: %v", err, err)
+	}
+	if wx.ClassifyError(err).Retryable() {
+		t.Error("Expected a request error not to be retryable")
+	}
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected the request never to be sent, got %d calls", got)
+	}
+
+	// The default retry condition gives up on a request error right away
+	attempts := 0
+	_, err = wx.Retry(func() (*http.Response, error) {
+		attempts++
+		return nil, requestErr
+	}, wx.WithRetries(3), wx.WithBackoff(0), wx.WithNoJitter())
+	if !errors.As(err, &requestErr) || attempts != 1 {
+		t.Errorf("Expected a single attempt returning the request error, got %d attempts and %v", attempts, err)
+	}
+}
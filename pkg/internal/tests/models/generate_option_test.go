@@ -0,0 +1,44 @@
+package test
+
+import (
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateOptionsMerge(t *testing.T) {
+	defaults := wx.GenerateOptions{}
+	for _, opt := range []wx.GenerateOption{
+		wx.WithTemperature(0.2),
+		wx.WithMaxNewTokens(200),
+		wx.WithDecodingMethod("sample"),
+		wx.WithStopSequences([]string{"\n\n"}),
+	} {
+		opt(&defaults)
+	}
+
+	override := wx.GenerateOptions{}
+	wx.WithTemperature(0.9)(&override)
+
+	merged := defaults.Merge(override)
+
+	if merged.Temperature == nil || *merged.Temperature != 0.9 {
+		t.Errorf("Expected temperature 0.9, got %v", merged.Temperature)
+	}
+	if merged.MaxNewTokens == nil || *merged.MaxNewTokens != 200 {
+		t.Errorf("Expected max new tokens 200, got %v", merged.MaxNewTokens)
+	}
+	if merged.DecodingMethod == nil || *merged.DecodingMethod != "sample" {
+		t.Errorf("Expected decoding method sample, got %v", merged.DecodingMethod)
+	}
+	if merged.StopSequences == nil || len(*merged.StopSequences) != 1 || (*merged.StopSequences)[0] != "\n\n" {
+		t.Errorf("Expected stop sequences to be kept, got %v", merged.StopSequences)
+	}
+	if merged.TopP != nil {
+		t.Errorf("Expected top p to stay unset, got %v", *merged.TopP)
+	}
+
+	if *defaults.Temperature != 0.2 {
+		t.Errorf("Expected defaults to be left unchanged, got temperature %v", *defaults.Temperature)
+	}
+}
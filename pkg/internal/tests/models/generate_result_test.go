@@ -0,0 +1,71 @@
+package test
+
+import (
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateTextResultTrimmedText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"  Paris\n\n", "Paris"},
+		{"Paris", "Paris"},
+		{"\t \n", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		result := wx.GenerateTextResult{Text: tt.text}
+		if got := result.TrimmedText(); got != tt.expected {
+			t.Errorf("TrimmedText(%q): expected %q, got %q", tt.text, tt.expected, got)
+		}
+	}
+}
+
+func TestGenerateTextResultTextWithoutPrompt(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		prompt   string
+		expected string
+	}{
+		{"echoed prompt", "Q: capital of France?\nA: Paris", "Q: capital of France?\n", "A: Paris"},
+		{"prompt absent", "A: Paris", "Q: capital of France?\n", "A: Paris"},
+		{"prompt not a prefix", "A: Paris. Q: capital of France?\n", "Q: capital of France?\n", "A: Paris. Q: capital of France?\n"},
+		{"only the prompt", "Say hello", "Say hello", ""},
+		{"empty output", "", "Say hello", ""},
+		{"empty prompt", "hello", "", "hello"},
+	}
+
+	for _, tt := range tests {
+		result := wx.GenerateTextResult{Text: tt.text}
+		if got := result.TextWithoutPrompt(tt.prompt); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestGenerateTextResultTextBeforeStopSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		stops    []string
+		expected string
+	}{
+		{"earliest stop", "Paris\n\nQuestion: Berlin?END", []string{"END", "\n\n"}, "Paris"},
+		{"no match", "Paris", []string{"END"}, "Paris"},
+		{"no stops", "Paris\n\n", nil, "Paris\n\n"},
+		{"empty stop ignored", "Paris", []string{""}, "Paris"},
+		{"empty output", "", []string{"END"}, ""},
+	}
+
+	for _, tt := range tests {
+		result := wx.GenerateTextResult{Text: tt.text}
+		if got := result.TextBeforeStopSequence(tt.stops...); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// writeSSEChunks writes generation chunks as server-sent events
+func writeSSEChunks(w http.ResponseWriter, chunks []string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for i, chunk := range chunks {
+		fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", i+1, chunk)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
+
+func TestGenerationStreamUsage(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wx.GenerateTextStreamEndpoint {
+			t.Errorf("Expected path %s, got %s", wx.GenerateTextStreamEndpoint, r.URL.Path)
+		}
+		writeSSEChunks(w, []string{
+			`{"results":[{"generated_text":"Hello","generated_token_count":1,"input_token_count":7,"stop_reason":"not_finished"}]}`,
+			`{"results":[{"generated_text":", ","generated_token_count":2,"input_token_count":7,"stop_reason":"not_finished"}]}`,
+			`{"results":[{"generated_text":"world","generated_token_count":3,"input_token_count":7,"stop_reason":"eos_token"}]}`,
+		})
+	})
+
+	stream, err := client.StreamGenerateText(context.Background(), "mock-model", "Say hello")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer stream.Close()
+
+	text := ""
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected no error while streaming, got %v", err)
+		}
+		text += result.Text
+	}
+
+	if text != "Hello, world" {
+		t.Errorf("Expected %q, got %q", "Hello, world", text)
+	}
+
+	usage := stream.Usage()
+	if usage.GeneratedTokenCount != 3 {
+		t.Errorf("Expected 3 generated tokens, got %d", usage.GeneratedTokenCount)
+	}
+	if usage.InputTokenCount != 7 {
+		t.Errorf("Expected 7 input tokens, got %d", usage.InputTokenCount)
+	}
+}
+
+func TestGenerateTextStreamChannel(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeSSEChunks(w, []string{
+			`{"results":[{"generated_text":"I am ","generated_token_count":2}]}`,
+			`{"results":[{"generated_text":"a model","generated_token_count":4}]}`,
+		})
+	})
+
+	dataChan, err := client.GenerateTextStream("mock-model", "Who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	text := ""
+	for data := range dataChan {
+		text += data.Text
+	}
+
+	if text != "I am a model" {
+		t.Errorf("Expected %q, got %q", "I am a model", text)
+	}
+}
+
+func TestGenerationStreamMidStreamError(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\nevent: message\ndata: {\"results\":[{\"generated_text\":\"one \"}]}\n\n")
+		fmt.Fprint(w, "id: 2\nevent: message\ndata: {\"results\":[{\"generated_text\":\"two \"}]}\n\n")
+		fmt.Fprint(w, "id: 3\nevent: error\ndata: {\"errors\":[{\"code\":\"downstream_request_failed\",\"message\":\"model crashed\"}],\"trace\":\"abc\",\"status_code\":503}\n\n")
+	})
+
+	stream, err := client.StreamGenerateText(context.Background(), "mock-model", "Count")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer stream.Close()
+
+	for _, expected := range []string{"one ", "two "} {
+		result, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Expected chunk %q, got error %v", expected, err)
+		}
+		if result.Text != expected {
+			t.Errorf("Expected chunk %q, got %q", expected, result.Text)
+		}
+	}
+
+	_, err = stream.Recv()
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) {
+		t.Fatalf("Expected WatsonxError after the error frame, got %v", err)
+	}
+
+	if wxErr.StatusCode != http.StatusServiceUnavailable || len(wxErr.Errors) != 1 || wxErr.Errors[0].Code != "downstream_request_failed" {
+		t.Errorf("Unexpected error details: %+v", wxErr)
+	}
+
+	if _, err := stream.Recv(); !errors.As(err, &wxErr) {
+		t.Errorf("Expected the stream to keep returning the error, got %v", err)
+	}
+}
+
+// oneByteBodyMiddleware delivers response bodies one byte per read to simulate fragmented network reads
+func oneByteBodyMiddleware(next http.RoundTripper) http.RoundTripper {
+	return wx.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{iotest.OneByteReader(resp.Body), resp.Body}
+		return resp, nil
+	})
+}
+
+func TestGenerationStreamFragmentedEvents(t *testing.T) {
+	large := strings.Repeat("x", 200*1024)
+
+	stream := strings.Join([]string{
+		// two events in one write, the first with a comment line
+		": keep-alive\nevent: message\ndata: {\"results\":[{\"generated_text\":\"one\"}]}\n\n",
+		"event: message\ndata: {\"results\":[{\"generated_text\":\"two\"}]}\n\n",
+		// an event larger than the default bufio.Scanner buffer
+		"event: message\ndata: {\"results\":[{\"generated_text\":\"" + large + "\"}]}\n\n",
+		// an event whose data spans several lines
+		"event: message\ndata: {\"results\":[{\"generated_text\":\"three\",\ndata: \"stop_reason\":\"eos_token\"}]}\n\n",
+	}, "")
+
+	client := newMockClientWithHttpOptions(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(stream))
+	}, []wx.HttpClientOption{wx.WithMiddleware(oneByteBodyMiddleware)})
+
+	generation, err := client.StreamGenerateText(context.Background(), "mock-model", "prompt")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer generation.Close()
+
+	var results []wx.GenerateTextResult
+	for {
+		result, err := generation.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected no error while streaming, got %v", err)
+		}
+		results = append(results, result)
+	}
+
+	expected := []string{"one", "two", large, "three"}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(results))
+	}
+
+	for i, text := range expected {
+		if results[i].Text != text {
+			t.Errorf("Expected event %d to have %d bytes of text, got %d", i, len(text), len(results[i].Text))
+		}
+	}
+
+	if results[3].StopReason != wx.EndOfSequenceToken {
+		t.Errorf("Expected the multi-line event to be parsed, got stop reason %q", results[3].StopReason)
+	}
+}
+
+func TestGenerationStreamMaxEventSize(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeSSEChunks(w, []string{`{"results":[{"generated_text":"` + strings.Repeat("x", 1024) + `"}]}`})
+	}, wx.WithMaxStreamEventSize(512))
+
+	generation, err := client.StreamGenerateText(context.Background(), "mock-model", "prompt")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer generation.Close()
+
+	if _, err := generation.Recv(); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("Expected bufio.ErrTooLong, got %v", err)
+	}
+}
+
+func TestGenerationStreamRead(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeSSEChunks(w, []string{
+			`{"results":[{"generated_text":"Hello","generated_token_count":1}]}`,
+			`{"results":[{"generated_text":"","generated_token_count":1}]}`,
+			`{"results":[{"generated_text":", world","generated_token_count":3,"stop_reason":"eos_token"}]}`,
+		})
+	})
+
+	stream, err := client.StreamGenerateText(context.Background(), "mock-model", "Say hello")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer stream.Close()
+
+	var buf strings.Builder
+	if _, err := io.Copy(&buf, stream); err != nil {
+		t.Fatalf("Expected no error while copying, got %v", err)
+	}
+
+	if buf.String() != "Hello, world" {
+		t.Errorf("Expected %q, got %q", "Hello, world", buf.String())
+	}
+}
+
+func TestGenerationStreamReadCancelled(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeSSEChunks(w, []string{`{"results":[{"generated_text":"Hello","generated_token_count":1}]}`})
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.StreamGenerateText(ctx, "mock-model", "Say hello")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer stream.Close()
+
+	buf := make([]byte, 64)
+	n, err := stream.Read(buf)
+	if err != nil || string(buf[:n]) != "Hello" {
+		t.Fatalf("Expected the first chunk, got %q and %v", buf[:n], err)
+	}
+
+	cancel()
+
+	if _, err := stream.Read(buf); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestDefaultHeadersAndPerCallOverride(t *testing.T) {
+	var received http.Header
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	}, wx.WithDefaultHeaders(http.Header{
+		"X-Routing-Tag": {"blue"},
+		"X-Experiment":  {"control"},
+	}))
+
+	ctx := wx.ContextWithHeaders(context.Background(), http.Header{
+		"X-Experiment": {"variant-b"},
+	})
+
+	results, err := client.GenerateBatch(ctx, []wx.GenerateTextRequest{{Model: "mock-model", Prompt: "Hello"}}, 1)
+	if err != nil || results[0].Err != nil {
+		t.Fatalf("Expected no error, got %v / %v", err, results[0].Err)
+	}
+
+	if received.Get("X-Routing-Tag") != "blue" {
+		t.Errorf("Expected default header X-Routing-Tag=blue, got %q", received.Get("X-Routing-Tag"))
+	}
+
+	if received.Get("X-Experiment") != "variant-b" {
+		t.Errorf("Expected per-call header to override the default, got %q", received.Get("X-Experiment"))
+	}
+
+	if received.Get("Authorization") != "Bearer mock-token" {
+		t.Errorf("Expected authorization header to be kept, got %q", received.Get("Authorization"))
+	}
+}
+
+func TestPerCallHeadersKeepAuthorization(t *testing.T) {
+	var received http.Header
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	}, wx.WithDefaultHeaders(http.Header{
+		"Authorization": {"Bearer default"},
+	}))
+
+	ctx := wx.ContextWithHeaders(context.Background(), http.Header{
+		"Authorization": {"Bearer per-call"},
+	})
+
+	if _, err := client.Generate(ctx, wx.GenerateTextRequest{Model: "mock-model", Prompt: "Hello"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if received.Get("Authorization") != "Bearer mock-token" {
+		t.Errorf("Expected the bearer token to be kept, got %q", received.Get("Authorization"))
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{
+		"Authorization": {"Bearer secret"},
+		"X-Routing-Tag": {"blue"},
+	}
+
+	redacted := wx.RedactHeaders(headers)
+
+	if redacted.Get("Authorization") == "Bearer secret" {
+		t.Error("Expected Authorization header to be redacted")
+	}
+
+	if redacted.Get("X-Routing-Tag") != "blue" {
+		t.Errorf("Expected non-sensitive header to be kept, got %q", redacted.Get("X-Routing-Tag"))
+	}
+
+	if headers.Get("Authorization") != "Bearer secret" {
+		t.Error("Expected the original headers to be left untouched")
+	}
+}
@@ -0,0 +1,100 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestHedgingFasterResponseWins(t *testing.T) {
+	var requests int32
+	slowCancelled := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"input":"hi"}` {
+			t.Errorf("Expected every copy to carry the body, got %q", body)
+		}
+
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// the first request is slow and only ends when cancelled
+			select {
+			case <-r.Context().Done():
+				close(slowCancelled)
+			case <-time.After(5 * time.Second):
+				w.Write([]byte("slow"))
+			}
+			return
+		}
+		w.Write([]byte("fast"))
+	}))
+	defer server.Close()
+
+	client := wx.NewHttpClient(
+		wx.WithIdempotencyKey(),
+		wx.WithHedging(20*time.Millisecond, 1),
+		wx.WithRetryOptions(wx.WithRetries(1)),
+	)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"input":"hi"}`))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := client.DoWithRetry(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "fast" {
+		t.Errorf("Expected the hedged request to win, got %q", body)
+	}
+
+	select {
+	case <-slowCancelled:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the slow request to be cancelled")
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected 2 requests, one hedge, got %d", got)
+	}
+}
+
+func TestHedgingSkipsNonIdempotentRequests(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := wx.NewHttpClient(
+		wx.WithHedging(5*time.Millisecond, 2),
+		wx.WithRetryOptions(wx.WithRetries(1)),
+	)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"input":"hi"}`))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := client.DoWithRetry(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected a POST without Idempotency-Key not to be hedged, got %d requests", got)
+	}
+}
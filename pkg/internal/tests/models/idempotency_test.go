@@ -0,0 +1,102 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// TestIdempotencyKeyStableAcrossRetries verifies that every attempt of one request carries the same key
+// and that separate requests get distinct keys.
+func TestIdempotencyKeyStableAcrossRetries(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(wx.IdempotencyKeyHeader))
+		attempt := len(keys)
+		mu.Unlock()
+
+		// fail the first two attempts of each request
+		if attempt%3 != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := wx.NewHttpClient(
+		wx.WithIdempotencyKey(),
+		wx.WithRetryOptions(wx.WithBackoff(0), wx.WithMaxJitter(0)),
+	)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"input":"hi"}`))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+
+		resp, err := client.DoWithRetry(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if len(keys) != 6 {
+		t.Fatalf("Expected 6 attempts, got %d", len(keys))
+	}
+
+	for i, key := range keys {
+		if key == "" {
+			t.Fatalf("Expected attempt %d to carry an idempotency key", i)
+		}
+	}
+
+	for _, request := range [][]string{keys[0:3], keys[3:6]} {
+		for _, key := range request[1:] {
+			if key != request[0] {
+				t.Errorf("Expected the same key across retries, got %s and %s", request[0], key)
+			}
+		}
+	}
+
+	if keys[0] == keys[3] {
+		t.Errorf("Expected distinct keys for separate requests, got %s twice", keys[0])
+	}
+}
+
+// TestIdempotencyKeyKeepsCallerValue verifies that a caller-supplied key is not replaced.
+func TestIdempotencyKeyKeepsCallerValue(t *testing.T) {
+	var received string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(wx.IdempotencyKeyHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := wx.NewHttpClient(wx.WithIdempotencyKey())
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set(wx.IdempotencyKeyHeader, "caller-key")
+
+	resp, err := client.DoWithRetry(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if received != "caller-key" {
+		t.Errorf("Expected caller-key, got %s", received)
+	}
+}
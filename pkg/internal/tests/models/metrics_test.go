@@ -0,0 +1,183 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// fakeMetricsRecorder records every call it receives
+type fakeMetricsRecorder struct {
+	mu        sync.Mutex
+	attempts  int
+	retries   int
+	latencies []int
+	durations []time.Duration
+}
+
+func (f *fakeMetricsRecorder) IncAttempt() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+}
+
+func (f *fakeMetricsRecorder) IncRetry() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retries++
+}
+
+func (f *fakeMetricsRecorder) ObserveLatency(status int, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latencies = append(f.latencies, status)
+	f.durations = append(f.durations, d)
+}
+
+// TestRetryMetricsRateLimitedThenSuccess checks the recorder calls across a 429-then-200 sequence.
+func TestRetryMetricsRateLimitedThenSuccess(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recorder := &fakeMetricsRecorder{}
+
+	resp, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithBackoff(0),
+		wx.WithMaxJitter(0),
+		wx.WithMetrics(recorder),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if recorder.attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", recorder.attempts)
+	}
+
+	if recorder.retries != 1 {
+		t.Errorf("Expected 1 retry, got %d", recorder.retries)
+	}
+
+	expectedStatuses := []int{http.StatusTooManyRequests, http.StatusOK}
+	if len(recorder.latencies) != len(expectedStatuses) {
+		t.Fatalf("Expected %d latency observations, got %d", len(expectedStatuses), len(recorder.latencies))
+	}
+	for i, status := range expectedStatuses {
+		if recorder.latencies[i] != status {
+			t.Errorf("Expected latency observation %d to have status %d, got %d", i, status, recorder.latencies[i])
+		}
+	}
+}
+
+// TestRetryMetricsLatencyUsesClock checks that attempt latency is measured with the clock set with WithClock.
+func TestRetryMetricsLatencyUsesClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recorder := &fakeMetricsRecorder{}
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			<-clock.After(3 * time.Second)
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+		wx.WithMetrics(recorder),
+		wx.WithClock(clock),
+		wx.WithTimer(clock),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(recorder.durations, []time.Duration{3 * time.Second}) {
+		t.Errorf("Expected a latency of 3s from the clock, got %v", recorder.durations)
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := map[int]string{
+		0:                              "error",
+		http.StatusOK:                  "2xx",
+		http.StatusCreated:             "2xx",
+		http.StatusFound:               "3xx",
+		http.StatusBadRequest:          "4xx",
+		http.StatusNotFound:            "4xx",
+		http.StatusTooManyRequests:     "429",
+		http.StatusInternalServerError: "5xx",
+		http.StatusServiceUnavailable:  "5xx",
+		999:                            "unknown",
+	}
+
+	for code, expected := range tests {
+		if got := wx.StatusClass(code); got != expected {
+			t.Errorf("StatusClass(%d): expected %q, got %q", code, expected, got)
+		}
+	}
+}
+
+// classMetricsRecorder also records the status classes
+type classMetricsRecorder struct {
+	fakeMetricsRecorder
+	classes []string
+}
+
+func (f *classMetricsRecorder) ObserveLatencyByClass(class string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.classes = append(f.classes, class)
+}
+
+func TestRetryMetricsStatusClass(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	recorder := &classMetricsRecorder{}
+
+	resp, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithBackoff(0),
+		wx.WithMaxJitter(0),
+		wx.WithMetrics(recorder),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	// Exact codes are still recorded along with the classes
+	if !reflect.DeepEqual(recorder.latencies, []int{429, 502, 200}) {
+		t.Errorf("Expected the exact status codes, got %v", recorder.latencies)
+	}
+	if !reflect.DeepEqual(recorder.classes, []string{"429", "5xx", "2xx"}) {
+		t.Errorf("Expected the status classes, got %v", recorder.classes)
+	}
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	var events []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events = append(events, "server")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recording := func(name string) wx.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return wx.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				events = append(events, name+" before")
+				resp, err := next.RoundTrip(req)
+				events = append(events, name+" after")
+				return resp, err
+			})
+		}
+	}
+
+	client := wx.NewHttpClient(
+		wx.WithMiddleware(recording("first"), recording("second")),
+		wx.WithTransport(server.Client().Transport),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := client.DoWithRetry(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	expected := []string{"first before", "second before", "server", "second after", "first after"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %v", expected, events)
+	}
+}
+
+func TestRequestInterceptor(t *testing.T) {
+	var attempts []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts = append(attempts, r.Header.Get("X-Attempt")+" "+r.URL.Query().Get("tag"))
+		if len(attempts) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	attempt := 0
+	client := wx.NewHttpClient(
+		wx.WithTransport(server.Client().Transport),
+		wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(0), wx.WithNoJitter()),
+		wx.WithRequestInterceptor(func(req *http.Request) error {
+			attempt++
+			req.Header.Add("X-Attempt", strconv.Itoa(attempt))
+			query := req.URL.Query()
+			query.Add("tag", "intercepted")
+			req.URL.RawQuery = query.Encode()
+			return nil
+		}),
+	)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"input":"hello"}`))
+	resp, err := client.DoWithRetry(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	// Each attempt is intercepted once, from the request as built
+	expected := []string{"1 intercepted", "2 intercepted", "3 intercepted"}
+	if !reflect.DeepEqual(attempts, expected) {
+		t.Errorf("Expected %v, got %v", expected, attempts)
+	}
+}
+
+func TestRequestInterceptorError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	interceptErr := errors.New("signing key unavailable")
+	client := wx.NewHttpClient(
+		wx.WithTransport(server.Client().Transport),
+		wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(0), wx.WithRetryIf(func(error) bool { return true })),
+		wx.WithRequestInterceptor(func(req *http.Request) error {
+			return interceptErr
+		}),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := client.DoWithRetry(req)
+
+	var requestErr *wx.RequestError
+	if !errors.As(err, &requestErr) || !errors.Is(err, interceptErr) {
+		t.Fatalf("Expected a *RequestError wrapping the interceptor error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected the request to be aborted, got %d calls", calls)
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+const modelSpecsPage = `{
+	"total_count": 1,
+	"resources": [{
+		"model_id": "ibm/granite-13b-instruct-v2",
+		"label": "granite-13b-instruct-v2",
+		"provider": "IBM",
+		"functions": [{"id": "text_generation"}],
+		"model_limits": {"max_sequence_length": 8192, "max_output_tokens": 4096},
+		"lifecycle": [{"id": "available", "start_date": "2023-12-01"}, {"id": "deprecated", "start_date": "2025-01-15"}]
+	}]
+}`
+
+func TestGetModelSpecCached(t *testing.T) {
+	var calls int32
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path != wx.ModelSpecsEndpoint || r.URL.Query().Get("filters") != "modelid_ibm/granite-13b-instruct-v2" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(modelSpecsPage))
+	}, wx.WithClientClock(clock), wx.WithModelSpecCacheTTL(time.Minute))
+
+	for i := 0; i < 2; i++ {
+		spec, err := client.GetModelSpec(context.Background(), "ibm/granite-13b-instruct-v2")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if spec.Limits.MaxSequenceLength != 8192 || len(spec.Lifecycle) != 2 || spec.Functions[0].ID != "text_generation" {
+			t.Errorf("Expected the spec to be parsed, got %+v", spec)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("Expected one HTTP call within the TTL, got %d", got)
+	}
+
+	// Past the TTL the spec is fetched again
+	clock.After(2 * time.Minute)
+	if _, err := client.GetModelSpec(context.Background(), "ibm/granite-13b-instruct-v2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("Expected the expired spec to be fetched again, got %d calls", got)
+	}
+
+	// An invalidated spec is fetched again
+	client.InvalidateModelSpec("ibm/granite-13b-instruct-v2")
+	if _, err := client.GetModelSpec(context.Background(), "ibm/granite-13b-instruct-v2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("Expected the invalidated spec to be fetched again, got %d calls", got)
+	}
+}
+
+func TestGetModelSpecConcurrent(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(modelSpecsPage))
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetModelSpec(context.Background(), "ibm/granite-13b-instruct-v2"); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			client.InvalidateModelSpec("")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGetModelSpecNotFound(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_count": 0, "resources": []}`))
+	})
+
+	if _, err := client.GetModelSpec(context.Background(), "unknown/model"); err == nil {
+		t.Fatal("Expected an error for an unknown model, got nil")
+	}
+}
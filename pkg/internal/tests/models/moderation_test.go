@@ -0,0 +1,112 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateTextWithHAPModeration(t *testing.T) {
+	var payload map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{
+			"results": [{
+				"generated_text": "[removed]",
+				"stop_reason": "eos_token",
+				"moderations": {
+					"hap": [{
+						"score": 0.97,
+						"input": false,
+						"position": {"start": 0, "end": 21},
+						"entity": "has_HAP"
+					}]
+				}
+			}]
+		}`))
+	})
+
+	result, err := client.GenerateText("mock-model", "Say something rude", wx.WithHAPModeration(0.75))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	moderations, ok := payload["moderations"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected moderations at the top level of the request, got %v", payload)
+	}
+
+	hap, _ := moderations["hap"].(map[string]interface{})
+	input, _ := hap["input"].(map[string]interface{})
+	if input["enabled"] != true || input["threshold"] != 0.75 {
+		t.Errorf("Expected enabled HAP input moderation with threshold 0.75, got %v", hap)
+	}
+
+	if parameters, ok := payload["parameters"].(map[string]interface{}); ok {
+		if _, found := parameters["moderations"]; found {
+			t.Error("Expected moderations not to be sent in parameters")
+		}
+	}
+
+	if result.Moderations == nil || len(result.Moderations.HAP) != 1 {
+		t.Fatalf("Expected one HAP moderation result, got %+v", result.Moderations)
+	}
+
+	detection := result.Moderations.HAP[0]
+	if detection.Score != 0.97 || detection.Input || detection.Entity != "has_HAP" {
+		t.Errorf("Unexpected HAP detection: %+v", detection)
+	}
+
+	if detection.Position.Start != 0 || detection.Position.End != 21 {
+		t.Errorf("Unexpected HAP detection position: %+v", detection.Position)
+	}
+}
+
+// TestGenerateTextModerationResultTypes parses HAP and PII detections into typed results with their offsets and scores
+func TestGenerateTextModerationResultTypes(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"results": [{
+				"generated_text": "You are a fool, call me at 555-0100",
+				"stop_reason": "eos_token",
+				"moderations": {
+					"hap": [
+						{"score": 0.91, "input": false, "position": {"start": 8, "end": 14}, "entity": "has_HAP"},
+						{"score": 0.82, "input": true, "position": {"start": 0, "end": 18}, "entity": "has_HAP"}
+					],
+					"pii": [
+						{"score": 0.88, "input": false, "position": {"start": 27, "end": 35}, "entity": "PhoneNumber"}
+					]
+				}
+			}]
+		}`))
+	})
+
+	result, err := client.GenerateText("mock-model", "Insult me", wx.WithHAPModeration(0.5), wx.WithPIIModeration(0.5))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []wx.ModerationResult{
+		{Type: wx.ModerationTypeHAP, Score: 0.91, Position: wx.ModerationPosition{Start: 8, End: 14}, Entity: "has_HAP"},
+		{Type: wx.ModerationTypeHAP, Score: 0.82, Input: true, Position: wx.ModerationPosition{Start: 0, End: 18}, Entity: "has_HAP"},
+		{Type: wx.ModerationTypePII, Score: 0.88, Position: wx.ModerationPosition{Start: 27, End: 35}, Entity: "PhoneNumber"},
+	}
+
+	all := result.Moderations.All()
+	if len(all) != len(expected) {
+		t.Fatalf("Expected %d detections, got %+v", len(expected), all)
+	}
+	for i := range expected {
+		if all[i] != expected[i] {
+			t.Errorf("Detection %d: expected %+v, got %+v", i, expected[i], all[i])
+		}
+	}
+
+	if redacted := result.Text[all[0].Position.Start:all[0].Position.End]; redacted != "a fool" {
+		t.Errorf("Expected the HAP offsets to cover 'a fool', got %q", redacted)
+	}
+}
@@ -0,0 +1,142 @@
+package test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// newClientCertificate generates a self-signed client certificate and a pool trusting it
+func newClientCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "watsonx-go test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	clientCert, clientCAs := newClientCertificate(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	tests := []struct {
+		name    string
+		options []wx.HttpClientOption
+		succeed bool
+	}{
+		{
+			name:    "without client certificate",
+			options: []wx.HttpClientOption{wx.WithRootCAs(rootCAs)},
+		},
+		{
+			name:    "with client certificate",
+			options: []wx.HttpClientOption{wx.WithRootCAs(rootCAs), wx.WithClientCertificate(clientCert)},
+			succeed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]wx.HttpClientOption{wx.WithTransport(&http.Transport{})}, tt.options...)
+			client := wx.NewHttpClient(options...)
+			defer client.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if tt.succeed && err != nil {
+				t.Errorf("Expected the request to succeed, got %v", err)
+			}
+			if !tt.succeed && err == nil {
+				t.Error("Expected the server to reject the request without a client certificate")
+			}
+		})
+	}
+}
+
+func TestHttpClientInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+
+	// The self-signed certificate of the server is rejected by default
+	verified := wx.NewHttpClient(wx.WithRetryOptions(wx.WithRetries(1)), wx.WithLogger(logger))
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := verified.Do(req); err == nil {
+		t.Fatal("Expected the self-signed certificate to be rejected")
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected no warning without the option, got %q", logs.String())
+	}
+
+	insecure := wx.NewHttpClient(wx.WithRetryOptions(wx.WithRetries(1)), wx.WithInsecureSkipVerify(), wx.WithLogger(logger))
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := insecure.Do(req)
+	if err != nil {
+		t.Fatalf("Expected verification to be skipped, got %v", err)
+	}
+	resp.Body.Close()
+
+	if !strings.Contains(logs.String(), "WithInsecureSkipVerify") {
+		t.Errorf("Expected a warning when verification is skipped, got %q", logs.String())
+	}
+}
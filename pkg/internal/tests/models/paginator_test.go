@@ -0,0 +1,114 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// mockPage is the page returned for a cursor
+type mockPage struct {
+	items      []string
+	nextCursor string
+}
+
+func TestPaginatorAll(t *testing.T) {
+	pages := map[string]mockPage{
+		"":      {items: []string{"a", "b"}, nextCursor: "page2"},
+		"page2": {items: []string{"c", "d"}, nextCursor: "page3"},
+		"page3": {items: []string{}, nextCursor: ""},
+	}
+
+	var cursors []string
+	paginator := wx.NewPaginator(func(ctx context.Context, cursor string) ([]string, string, error) {
+		cursors = append(cursors, cursor)
+		page := pages[cursor]
+		return page.items, page.nextCursor, nil
+	})
+
+	all, err := paginator.All(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"a", "b", "c", "d"}
+	if !reflect.DeepEqual(all, expected) {
+		t.Fatalf("Expected %v, got %v", expected, all)
+	}
+
+	if !reflect.DeepEqual(cursors, []string{"", "page2", "page3"}) {
+		t.Fatalf("Expected three page fetches, got cursors %v", cursors)
+	}
+
+	if paginator.HasNext() {
+		t.Fatal("Expected no more pages")
+	}
+}
+
+func TestPaginatorNext(t *testing.T) {
+	paginator := wx.NewPaginator(func(ctx context.Context, cursor string) ([]int, string, error) {
+		if cursor == "" {
+			return []int{1, 2}, "next", nil
+		}
+		return []int{3}, "", nil
+	})
+
+	first, err := paginator.Next(context.Background())
+	if err != nil || !reflect.DeepEqual(first, []int{1, 2}) {
+		t.Fatalf("Expected first page [1 2], got %v (err %v)", first, err)
+	}
+
+	second, err := paginator.Next(context.Background())
+	if err != nil || !reflect.DeepEqual(second, []int{3}) {
+		t.Fatalf("Expected second page [3], got %v (err %v)", second, err)
+	}
+
+	third, err := paginator.Next(context.Background())
+	if err != nil || third != nil {
+		t.Fatalf("Expected no more items, got %v (err %v)", third, err)
+	}
+}
+
+func TestPaginatorFetchError(t *testing.T) {
+	fetchErr := errors.New("fetch failed")
+
+	paginator := wx.NewPaginator(func(ctx context.Context, cursor string) ([]string, string, error) {
+		if cursor == "" {
+			return []string{"a"}, "next", nil
+		}
+		return nil, "", fetchErr
+	})
+
+	all, err := paginator.All(context.Background())
+	if !errors.Is(err, fetchErr) {
+		t.Fatalf("Expected fetch error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(all, []string{"a"}) {
+		t.Fatalf("Expected items fetched before the error, got %v", all)
+	}
+}
+
+func TestPaginatorRepeatedCursor(t *testing.T) {
+	var calls int
+	paginator := wx.NewPaginator(func(ctx context.Context, cursor string) ([]string, string, error) {
+		calls++
+		return []string{"a"}, "same", nil
+	})
+
+	all, err := paginator.All(context.Background())
+	if !errors.Is(err, wx.ErrRepeatedCursor) {
+		t.Fatalf("Expected ErrRepeatedCursor, got %v", err)
+	}
+
+	if !reflect.DeepEqual(all, []string{"a"}) || calls != 2 {
+		t.Fatalf("Expected to stop at the second page, got %v after %d calls", all, calls)
+	}
+
+	if paginator.HasNext() {
+		t.Errorf("Expected no more pages after a repeated cursor")
+	}
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestPingHealthy(t *testing.T) {
+	var path string
+	var query url.Values
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.Query()
+		w.Write([]byte(`{"total_count":0,"resources":[]}`))
+	})
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if path != wx.DeploymentEndpoint {
+		t.Errorf("Expected path %s, got %s", wx.DeploymentEndpoint, path)
+	}
+	if query.Get("limit") != "1" {
+		t.Errorf("Expected limit 1, got %q", query.Get("limit"))
+	}
+	if query.Get("project_id") != "mock-project-id" {
+		t.Errorf("Expected the request to be scoped to the project, got %q", query.Get("project_id"))
+	}
+}
+
+func TestPingUnauthorized(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errors":[{"code":"authentication_token_expired","message":"Token expired"}],"status_code":401}`))
+	})
+
+	err := client.Ping(context.Background())
+
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) || wxErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected a 401 *WatsonxError, got %v", err)
+	}
+	if class := wx.ClassifyError(err); class != wx.ErrorClassAuth {
+		t.Errorf("Expected ErrorClassAuth, got %v", class)
+	}
+}
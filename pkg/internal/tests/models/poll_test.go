@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestPollUntilTerminalStatus(t *testing.T) {
+	statuses := []string{"pending", "running", "completed"}
+	polls := 0
+
+	status, err := wx.PollUntil(context.Background(), func() (string, error) {
+		status := statuses[polls]
+		polls++
+		return status, nil
+	}, []string{"completed", "failed"}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if status != "completed" {
+		t.Errorf("Expected completed, got %s", status)
+	}
+
+	if polls != len(statuses) {
+		t.Errorf("Expected %d polls, got %d", len(statuses), polls)
+	}
+}
+
+func TestPollUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	polls := 0
+
+	status, err := wx.PollUntil(ctx, func() (string, error) {
+		polls++
+		if polls == 1 {
+			cancel()
+		}
+		return "running", nil
+	}, []string{"completed"}, time.Hour)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if status != "running" {
+		t.Errorf("Expected the last status to be returned, got %s", status)
+	}
+}
+
+func TestPollUntilFetchError(t *testing.T) {
+	fetchErr := errors.New("boom")
+
+	_, err := wx.PollUntil(context.Background(), func() (string, error) {
+		return "", fetchErr
+	}, []string{"completed"}, time.Millisecond)
+
+	if !errors.Is(err, fetchErr) {
+		t.Fatalf("Expected the fetch error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestPromptTemplateRender(t *testing.T) {
+	template := wx.NewPromptTemplate("Translate '{{text}}' to {{ language }}. Answer with {\"translation\": ...}")
+
+	result, err := template.Render(map[string]string{
+		"text":     "hello",
+		"language": "French",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "Translate 'hello' to French. Answer with {\"translation\": ...}"
+	if result != expected {
+		t.Fatalf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestPromptTemplateMissingVariables(t *testing.T) {
+	template := wx.NewPromptTemplate("{{greeting}}, {{name}}! Today is {{day}}.")
+
+	_, err := template.Render(map[string]string{
+		"greeting": "Hello",
+	})
+	if err == nil {
+		t.Fatal("Expected error for missing variables, got nil")
+	}
+
+	if !errors.Is(err, wx.ErrMissingPromptVariable) {
+		t.Fatalf("Expected ErrMissingPromptVariable, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "name") || !strings.Contains(err.Error(), "day") {
+		t.Fatalf("Expected error to name the missing variables, got %v", err)
+	}
+}
+
+func TestPromptTemplateEscapedBraces(t *testing.T) {
+	template := wx.NewPromptTemplate(`Use \{{name}} as a placeholder for {{name}}`)
+
+	result, err := template.Render(map[string]string{
+		"name": "Alice",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "Use {{name}} as a placeholder for Alice"
+	if result != expected {
+		t.Fatalf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestPromptTemplateUnterminatedPlaceholder(t *testing.T) {
+	template := wx.NewPromptTemplate("Hello {{name")
+
+	if _, err := template.Render(map[string]string{"name": "Alice"}); err == nil {
+		t.Fatal("Expected error for unterminated placeholder, got nil")
+	}
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestWithProxyURL(t *testing.T) {
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a proxied request carries the absolute URL of the target
+		if r.URL.Host != "watsonx.invalid" {
+			t.Errorf("Expected a request for watsonx.invalid, got %s", r.URL)
+		}
+		atomic.AddInt32(&proxied, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	client := wx.NewHttpClient(wx.WithTransport(&http.Transport{}), wx.WithProxyURL(proxy.URL))
+
+	req, err := http.NewRequest(http.MethodGet, "http://watsonx.invalid/ml/v1/text/generation", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if atomic.LoadInt32(&proxied) != 1 {
+		t.Errorf("Expected the request to go through the proxy, got %d proxied requests", proxied)
+	}
+}
+
+func TestWithProxyURLInvalid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the request not to be sent")
+	}))
+	defer server.Close()
+
+	client := wx.NewHttpClient(wx.WithTransport(&http.Transport{}), wx.WithProxyURL("://proxy"))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	_, err = client.Do(req)
+	if err == nil || !strings.Contains(err.Error(), "invalid proxy URL") {
+		t.Errorf("Expected an invalid proxy URL error, got %v", err)
+	}
+}
+
+func TestWithProxyFromEnvironmentDisabled(t *testing.T) {
+	var reached int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reached, 1)
+	}))
+	defer server.Close()
+
+	// a disabled proxy overrides the one of the transport
+	transport := &http.Transport{Proxy: func(*http.Request) (*url.URL, error) {
+		return url.Parse("http://127.0.0.1:1")
+	}}
+	client := wx.NewHttpClient(wx.WithTransport(transport), wx.WithProxyFromEnvironment(false))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if atomic.LoadInt32(&reached) != 1 {
+		t.Error("Expected the request to reach the server directly")
+	}
+}
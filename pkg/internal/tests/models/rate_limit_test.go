@@ -0,0 +1,53 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateRateLimitInfo(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "120")
+		w.Header().Set("X-RateLimit-Remaining", "17")
+		w.Header().Set("X-RateLimit-Reset", "30")
+		w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello", "stop_reason": "eos_token"}]}`))
+	})
+
+	response, err := client.Generate(context.Background(), wx.GenerateTextRequest{Model: "mock-model", Prompt: "Say hello"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	info := response.RateLimit
+	if info == nil {
+		t.Fatal("Expected rate limit info on the response")
+	}
+
+	if info.Limit != 120 || info.Remaining != 17 {
+		t.Errorf("Expected a limit of 120 with 17 remaining, got %d and %d", info.Limit, info.Remaining)
+	}
+
+	if until := time.Until(info.Reset); until <= 25*time.Second || until > 30*time.Second {
+		t.Errorf("Expected the window to reset in about 30s, got %v", until)
+	}
+}
+
+func TestParseRateLimitInfo(t *testing.T) {
+	if info := wx.ParseRateLimitInfo(http.Header{}); info != nil {
+		t.Errorf("Expected nil without rate limit headers, got %+v", info)
+	}
+
+	reset := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", "1893456000")
+
+	info := wx.ParseRateLimitInfo(header)
+	if info == nil || info.Remaining != 0 || !info.Reset.Equal(reset) {
+		t.Errorf("Expected no remaining requests until %v, got %+v", reset, info)
+	}
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// newInspectionClient creates a mock client that fails the test if any request other than IAM is sent
+func newInspectionClient(t *testing.T) *wx.Client {
+	return newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request to be sent, got %s %s", r.Method, r.URL.Path)
+	})
+}
+
+func TestBuildGenerateRequest(t *testing.T) {
+	client := newInspectionClient(t)
+
+	req, err := client.BuildGenerateRequest(wx.GenerateTextRequest{
+		Model:   "ibm/granite-13b-chat-v2",
+		Prompt:  "Hi, who are you?",
+		Options: []wx.GenerateOption{wx.WithMaxNewTokens(20), wx.WithTemperature(0.5)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if req.Method != http.MethodPost {
+		t.Errorf("Expected POST, got %s", req.Method)
+	}
+
+	if req.URL.Scheme != "https" {
+		t.Errorf("Expected https scheme, got %s", req.URL.Scheme)
+	}
+
+	if req.URL.Path != wx.GenerateTextEndpoint {
+		t.Errorf("Expected path %s, got %s", wx.GenerateTextEndpoint, req.URL.Path)
+	}
+
+	if req.URL.Query().Get("version") != wx.DefaultAPIVersion {
+		t.Errorf("Expected version %s, got %s", wx.DefaultAPIVersion, req.URL.Query().Get("version"))
+	}
+
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON content type, got %s", req.Header.Get("Content-Type"))
+	}
+
+	if req.Header.Get("Authorization") != "Bearer mock-token" {
+		t.Errorf("Expected bearer authorization header, got %q", req.Header.Get("Authorization"))
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Expected JSON body, got %s", body)
+	}
+
+	if payload["model_id"] != "ibm/granite-13b-chat-v2" {
+		t.Errorf("Expected model_id in body, got %v", payload["model_id"])
+	}
+
+	if payload["input"] != "Hi, who are you?" {
+		t.Errorf("Expected input in body, got %v", payload["input"])
+	}
+
+	if payload["project_id"] != "mock-project-id" {
+		t.Errorf("Expected project_id in body, got %v", payload["project_id"])
+	}
+
+	parameters, ok := payload["parameters"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected parameters in body, got %v", payload["parameters"])
+	}
+
+	if parameters["max_new_tokens"] != float64(20) || parameters["temperature"] != 0.5 {
+		t.Errorf("Expected parameters to be serialized, got %v", parameters)
+	}
+}
+
+func TestBuildGenerateRequestWithTunedModel(t *testing.T) {
+	client := newInspectionClient(t)
+	assetID := "0f4a2c6e-tuned-asset"
+
+	req, err := client.BuildGenerateRequest(wx.GenerateTextRequest{
+		Model:   "ibm/granite-13b-instruct-v2",
+		Prompt:  "Hello",
+		Options: []wx.GenerateOption{wx.WithTunedModel(assetID)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedPath := fmt.Sprintf(wx.DeploymentGenerateTextEndpointFormat, assetID)
+	if req.URL.Path != expectedPath {
+		t.Errorf("Expected path %s, got %s", expectedPath, req.URL.Path)
+	}
+
+	var payload map[string]interface{}
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		t.Fatalf("Expected JSON body, got %v", err)
+	}
+	if _, ok := payload["model_id"]; ok {
+		t.Errorf("Expected no model_id in the tuned model request, got %v", payload["model_id"])
+	}
+	if payload["input"] != "Hello" {
+		t.Errorf("Expected input in body, got %v", payload["input"])
+	}
+}
+
+func TestBuildChatAndEmbeddingRequests(t *testing.T) {
+	client := newInspectionClient(t)
+
+	chatReq, err := client.BuildChatHTTPRequest(
+		"meta-llama/llama-3-3-70b-instruct",
+		[]wx.ChatMessage{wx.CreateUserMessage("Hello")},
+		wx.WithChatMaxTokens(10),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if chatReq.URL.Path != wx.ChatEndpoint {
+		t.Errorf("Expected path %s, got %s", wx.ChatEndpoint, chatReq.URL.Path)
+	}
+
+	embedReq, err := client.BuildEmbeddingRequest("ibm/slate-30m-english-rtrvr", []string{"Hello"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if embedReq.URL.Path != wx.EmbeddingEndpoint {
+		t.Errorf("Expected path %s, got %s", wx.EmbeddingEndpoint, embedReq.URL.Path)
+	}
+
+	var payload wx.EmbeddingPayload
+	if err := json.NewDecoder(embedReq.Body).Decode(&payload); err != nil {
+		t.Fatalf("Expected JSON body, got error %v", err)
+	}
+
+	if len(payload.Inputs) != 1 || payload.Inputs[0] != "Hello" {
+		t.Errorf("Expected inputs in body, got %v", payload.Inputs)
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestRerank(t *testing.T) {
+	var path string
+	var payload wx.RerankPayload
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&payload)
+		// results deliberately out of score order
+		w.Write([]byte(`{
+			"model_id": "cross-encoder/ms-marco-minilm-l-12-v2",
+			"results": [
+				{"index": 0, "score": 0.12, "input": {"text": "Paris is in France."}},
+				{"index": 2, "score": 0.91, "input": {"text": "Go is a programming language."}},
+				{"index": 1, "score": 0.47, "input": {"text": "Go has goroutines."}}
+			],
+			"created_at": "2024-10-01T00:00:00.000Z",
+			"input_token_count": 42
+		}`))
+	})
+
+	documents := []string{
+		"Paris is in France.",
+		"Go has goroutines.",
+		"Go is a programming language.",
+	}
+
+	response, err := client.Rerank(context.Background(), wx.RerankRequest{
+		Model:     "cross-encoder/ms-marco-minilm-l-12-v2",
+		Query:     "What is Go?",
+		Documents: documents,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if path != wx.RerankEndpoint {
+		t.Errorf("Expected path %s, got %s", wx.RerankEndpoint, path)
+	}
+
+	if payload.Query != "What is Go?" || len(payload.Inputs) != len(documents) {
+		t.Errorf("Expected query and %d inputs in request, got %+v", len(documents), payload)
+	}
+
+	expectedIndices := []int{2, 1, 0}
+	if len(response.Results) != len(expectedIndices) {
+		t.Fatalf("Expected %d results, got %d", len(expectedIndices), len(response.Results))
+	}
+
+	for i, index := range expectedIndices {
+		result := response.Results[i]
+		if result.Index != index {
+			t.Errorf("Expected result %d to have index %d, got %d", i, index, result.Index)
+		}
+		if result.Input == nil || result.Input.Text != documents[result.Index] {
+			t.Errorf("Expected result %d to map back to %q, got %+v", i, documents[result.Index], result.Input)
+		}
+	}
+}
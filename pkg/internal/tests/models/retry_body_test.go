@@ -0,0 +1,119 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// countingReader records how many bytes have been read from it
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += n
+	return n, err
+}
+
+// bodyRecordingTransport fails every request after recording what was read from the body
+type bodyRecordingTransport struct {
+	source        *countingReader
+	readBeforeRun []int
+	bodies        []string
+}
+
+func (b *bodyRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.readBeforeRun = append(b.readBeforeRun, b.source.read)
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	b.bodies = append(b.bodies, string(body))
+
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Header:     http.Header{},
+		Request:    req,
+	}, nil
+}
+
+// TestMaxRetryBodySizeSingleAttempt verifies that an oversized body is streamed once without being buffered.
+func TestMaxRetryBodySizeSingleAttempt(t *testing.T) {
+	const limit = 100
+	payload := strings.Repeat("x", 10*limit)
+
+	source := &countingReader{r: strings.NewReader(payload)}
+	transport := &bodyRecordingTransport{source: source}
+
+	client := wx.NewHttpClient(
+		wx.WithTransport(transport),
+		wx.WithMaxRetryBodySize(limit),
+		wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(0), wx.WithNoJitter()),
+	)
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com", io.NopCloser(source))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	_, err = client.DoWithRetry(req)
+
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) {
+		t.Fatalf("Expected WatsonxError, got %v", err)
+	}
+
+	if len(transport.bodies) != 1 {
+		t.Fatalf("Expected a single attempt, got %d", len(transport.bodies))
+	}
+
+	if transport.readBeforeRun[0] > limit+1 {
+		t.Errorf("Expected at most %d bytes buffered before sending, got %d", limit+1, transport.readBeforeRun[0])
+	}
+
+	if transport.bodies[0] != payload {
+		t.Errorf("Expected the full body to be sent, got %d bytes", len(transport.bodies[0]))
+	}
+}
+
+// TestMaxRetryBodySizeRetriesSmallBody verifies that bodies within the limit are still retried.
+func TestMaxRetryBodySizeRetriesSmallBody(t *testing.T) {
+	payload := `{"input":"hi"}`
+
+	source := &countingReader{r: strings.NewReader(payload)}
+	transport := &bodyRecordingTransport{source: source}
+
+	client := wx.NewHttpClient(
+		wx.WithTransport(transport),
+		wx.WithMaxRetryBodySize(100),
+		wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(0), wx.WithNoJitter()),
+	)
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com", source)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	if _, err := client.DoWithRetry(req); err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	if len(transport.bodies) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(transport.bodies))
+	}
+
+	for i, body := range transport.bodies {
+		if body != payload {
+			t.Errorf("Expected attempt %d to send %q, got %q", i+1, payload, body)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// TestRetryBudget verifies that retries stop once the shared budget is depleted and resume once it refills.
+func TestRetryBudget(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	budget := wx.NewRetryBudget(2, 10)
+	client := wx.NewHttpClient(
+		wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(0), wx.WithNoJitter(), wx.WithRetryBudget(budget)),
+	)
+
+	attempts := func() int32 {
+		atomic.StoreInt32(&calls, 0)
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+
+		if _, err := client.DoWithRetry(req); err == nil {
+			t.Fatalf("Expected an error")
+		}
+		return atomic.LoadInt32(&calls)
+	}
+
+	if got := attempts(); got != 3 {
+		t.Errorf("Expected the full budget to allow 3 attempts, got %d", got)
+	}
+
+	if got := attempts(); got != 1 {
+		t.Errorf("Expected a depleted budget to allow only the initial attempt, got %d", got)
+	}
+
+	// 10 tokens per second refill the 2 tokens within 200ms
+	time.Sleep(250 * time.Millisecond)
+
+	if got := attempts(); got != 3 {
+		t.Errorf("Expected a replenished budget to allow 3 attempts, got %d", got)
+	}
+}
@@ -0,0 +1,173 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// timeoutError is a net.Error that reports a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryOnTransientNetworkErrors(t *testing.T) {
+	opErr := func(err error) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", err)}
+	}
+	urlErr := func(err error) error {
+		return &url.Error{Op: "Post", URL: "https://us-south.ml.cloud.ibm.com", Err: err}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"timeout", urlErr(timeoutError{}), true},
+		{"deadline exceeded", urlErr(context.DeadlineExceeded), true},
+		{"connection refused", urlErr(opErr(syscall.ECONNREFUSED)), true},
+		{"connection reset", fmt.Errorf("read failed: %w", opErr(syscall.ECONNRESET)), true},
+		{"eof", urlErr(io.EOF), true},
+		{"unexpected eof", fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF), true},
+		{"dns not found", urlErr(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "invalid.host", IsNotFound: true}}), false},
+		{"dns timeout", urlErr(&net.DNSError{Err: "timeout", Name: "slow.host", IsTimeout: true}), false},
+		{"unsupported scheme", urlErr(errors.New("unsupported protocol scheme \"htp\"")), false},
+		{"http status", &wx.WatsonxError{StatusCode: http.StatusServiceUnavailable}, false},
+		{"generic", errors.New("something went wrong"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wx.RetryOnTransientNetworkErrors(tt.err); got != tt.expected {
+				t.Errorf("Expected %v for %v, got %v", tt.expected, tt.err, got)
+			}
+		})
+	}
+}
+
+func TestRetryWithRetryOnConnectionErrorsSkipsPermanentErrors(t *testing.T) {
+	attempts := 0
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			attempts++
+			return http.Get("htp://invalid-scheme")
+		},
+		wx.WithBackoff(0),
+		wx.WithMaxJitter(0),
+		wx.WithRetryOnConnectionErrors(),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected a single attempt for a permanent error, got %d", attempts)
+	}
+}
+
+func TestWithRetryableStatusCodes(t *testing.T) {
+	tests := []struct {
+		status   int
+		attempts int32
+	}{
+		{status: http.StatusInternalServerError, attempts: 3},
+		{status: http.StatusBadRequest, attempts: 1},
+		{status: http.StatusConflict, attempts: 3},
+		{status: http.StatusTooEarly, attempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			_, err := wx.Retry(
+				func() (*http.Response, error) {
+					return http.Get(server.URL)
+				},
+				wx.WithRetries(3),
+				wx.WithBackoff(0),
+				wx.WithNoJitter(),
+				wx.WithRetryableStatusCodes(409, 425, 429, 500, 502, 503, 504),
+			)
+
+			var wxErr *wx.WatsonxError
+			if !errors.As(err, &wxErr) || wxErr.StatusCode != tt.status {
+				t.Fatalf("Expected a WatsonxError with status %d, got %v", tt.status, err)
+			}
+
+			if got := atomic.LoadInt32(&calls); got != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, got)
+			}
+		})
+	}
+}
+
+func TestWithRetryOnErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []wx.RetryOption
+		attempts int32
+	}{
+		{name: "default", attempts: 1},
+		{name: "model_loading", options: []wx.RetryOption{wx.WithRetryOnErrorCodes("model_loading")}, attempts: 3},
+		{name: "other code", options: []wx.RetryOption{wx.WithRetryOnErrorCodes("service_unavailable")}, attempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors":[{"code":"model_loading","message":"Model is loading"}],"status_code":400}`))
+			}))
+			defer server.Close()
+
+			options := append([]wx.RetryOption{
+				wx.WithRetries(3),
+				wx.WithBackoff(0),
+				wx.WithNoJitter(),
+			}, tt.options...)
+
+			_, err := wx.Retry(
+				func() (*http.Response, error) {
+					return http.Get(server.URL)
+				},
+				options...,
+			)
+
+			var wxErr *wx.WatsonxError
+			if !errors.As(err, &wxErr) || wxErr.StatusCode != http.StatusBadRequest {
+				t.Fatalf("Expected a WatsonxError with status 400, got %v", err)
+			}
+
+			if got := atomic.LoadInt32(&calls); got != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, got)
+			}
+		})
+	}
+}
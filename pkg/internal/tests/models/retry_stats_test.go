@@ -0,0 +1,55 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// TestRetryStatsConcurrentFailures runs concurrent failing requests and checks the aggregate counts.
+func TestRetryStatsConcurrentFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	stats := wx.NewRetryStats()
+	client := wx.NewHttpClient(
+		wx.WithRetryStats(stats),
+		wx.WithRetryOptions(wx.WithRetries(3), wx.WithBackoff(0), wx.WithNoJitter()),
+	)
+
+	const requests = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Errorf("Failed to create request: %v", err)
+				return
+			}
+
+			if _, err := client.DoWithRetry(req); err == nil {
+				t.Errorf("Expected an error")
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected := wx.RetryStatsSnapshot{
+		Attempts: requests * 3,
+		Retries:  requests * 2,
+		Failures: requests,
+	}
+
+	if snapshot := stats.Snapshot(); snapshot != expected {
+		t.Errorf("Expected %+v, got %+v", expected, snapshot)
+	}
+}
@@ -1,10 +1,19 @@
 package test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"math"
+	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -198,3 +207,810 @@ func TestRetryReturnsWatsonxErrorWithEmptyBody(t *testing.T) {
 		t.Errorf("Expected no error details, got %d", len(wxErr.Errors))
 	}
 }
+
+// TestRetryBackoffClampedToContextDeadline validates that a backoff longer than the remaining
+// context time wakes up at the deadline and returns the context error.
+func TestRetryBackoffClampedToContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	deadline := 200 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+
+	startTime := time.Now()
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithContext(ctx),
+		wx.WithBackoff(2*time.Second),
+		wx.WithMaxJitter(0),
+	)
+
+	elapsedTime := time.Since(startTime)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	var timeoutErr *wx.RetryTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected RetryTimeoutError, got %T", err)
+	}
+
+	// The clamped backoff wakes up at the deadline, which can leave time for one more attempt
+	if timeoutErr.Attempts < 1 || timeoutErr.Attempts > 2 {
+		t.Errorf("Expected 1 or 2 attempts before the deadline, got %d", timeoutErr.Attempts)
+	}
+
+	var wxErr *wx.WatsonxError
+	if !errors.As(timeoutErr.LastErr, &wxErr) {
+		t.Errorf("Expected the last error to be a WatsonxError, got %v", timeoutErr.LastErr)
+	}
+
+	if elapsedTime < deadline || elapsedTime > deadline+500*time.Millisecond {
+		t.Errorf("Expected retry to stop at ~%v, but took %v", deadline, elapsedTime)
+	}
+}
+
+// recordingTimer is a Timer that fires immediately and records every requested delay
+type recordingTimer struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (r *recordingTimer) After(d time.Duration) <-chan time.Time {
+	r.mu.Lock()
+	r.delays = append(r.delays, d)
+	r.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	ch <- time.Now()
+	return ch
+}
+
+func (r *recordingTimer) Delays() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Duration(nil), r.delays...)
+}
+
+// newRetryAfterServer returns a server that answers 429 with the given Retry-After header once, then 200
+func newRetryAfterServer(retryAfter string) *httptest.Server {
+	var calls int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// TestRetryExponentialBackoffWithRetryAfter validates that the delay is the longest of
+// the exponential backoff and the Retry-After requested by the server.
+func TestRetryExponentialBackoffWithRetryAfter(t *testing.T) {
+	tests := []struct {
+		retryAfter    string
+		expectedDelay time.Duration
+	}{
+		{"1", 4 * time.Second},
+		{"10", 10 * time.Second},
+		{"", 4 * time.Second},
+	}
+
+	for _, tt := range tests {
+		server := newRetryAfterServer(tt.retryAfter)
+		timer := &recordingTimer{}
+
+		resp, err := wx.Retry(
+			func() (*http.Response, error) {
+				return http.Get(server.URL)
+			},
+			wx.WithExponentialBackoff(4*time.Second),
+			wx.WithMaxJitter(0),
+			wx.WithTimer(timer),
+		)
+		server.Close()
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+
+		delays := timer.Delays()
+		if len(delays) != 1 || delays[0] != tt.expectedDelay {
+			t.Errorf("Retry-After %q: expected a single delay of %v, got %v", tt.retryAfter, tt.expectedDelay, delays)
+		}
+	}
+}
+
+// TestRetryExponentialBackoffGrowth validates that the backoff doubles on every retry up to the maximum.
+func TestRetryExponentialBackoffGrowth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	timer := &recordingTimer{}
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithRetries(5),
+		wx.WithExponentialBackoff(time.Second),
+		wx.WithMaxBackoff(5*time.Second),
+		wx.WithMaxJitter(0),
+		wx.WithTimer(timer),
+	)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if !reflect.DeepEqual(timer.Delays(), expected) {
+		t.Errorf("Expected delays %v, got %v", expected, timer.Delays())
+	}
+}
+
+// TestRetrySaturatedBackoffWithJitter validates that the jitter added to a saturated backoff does not overflow
+// into a negative delay, which would retry without waiting.
+func TestRetrySaturatedBackoffWithJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff wx.RetryOption
+	}{
+		{"exponential", wx.WithExponentialBackoff(time.Hour)},
+		{"linear", wx.WithLinearBackoff(math.MaxInt64 / 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			timer := &recordingTimer{}
+
+			_, err := wx.Retry(
+				func() (*http.Response, error) {
+					return http.Get(server.URL)
+				},
+				wx.WithRetries(30),
+				tt.backoff,
+				wx.WithMaxJitter(time.Second),
+				wx.WithRandSource(rand.New(rand.NewSource(1))),
+				wx.WithTimer(timer),
+			)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			for i, delay := range timer.Delays() {
+				if delay <= 0 {
+					t.Fatalf("Expected a positive delay after attempt %d, got %v", i+1, delay)
+				}
+			}
+		})
+	}
+}
+
+// TestRetryWithRandSourceIsDeterministic validates that a seeded source yields the same jitter on every run.
+func TestRetryWithRandSourceIsDeterministic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	runDelays := func() []time.Duration {
+		timer := &recordingTimer{}
+		wx.Retry(
+			func() (*http.Response, error) {
+				return http.Get(server.URL)
+			},
+			wx.WithRetries(4),
+			wx.WithBackoff(0),
+			wx.WithMaxJitter(time.Second),
+			wx.WithRandSource(rand.New(rand.NewSource(42))),
+			wx.WithTimer(timer),
+		)
+		return timer.Delays()
+	}
+
+	first, second := runDelays(), runDelays()
+
+	if len(first) != 4 {
+		t.Fatalf("Expected 4 delays, got %v", first)
+	}
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected identical jitter sequences, got %v and %v", first, second)
+	}
+
+	allEqual := true
+	for _, d := range first[1:] {
+		allEqual = allEqual && d == first[0]
+	}
+	if allEqual {
+		t.Errorf("Expected jitter to vary between attempts, got %v", first)
+	}
+}
+
+// TestRetryWithNoJitter validates that every retry waits exactly the configured backoff.
+func TestRetryWithNoJitter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	backoff := 250 * time.Millisecond
+	timer := &recordingTimer{}
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithRetries(5),
+		wx.WithBackoff(backoff),
+		wx.WithNoJitter(),
+		wx.WithTimer(timer),
+	)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	delays := timer.Delays()
+	if len(delays) != 5 {
+		t.Fatalf("Expected 5 delays, got %v", delays)
+	}
+
+	for i, d := range delays {
+		if d != backoff {
+			t.Errorf("Expected delay %d to be exactly %v, got %v", i, backoff, d)
+		}
+	}
+}
+
+// TestRetryWithNegativeJitter validates that a negative jitter is treated as zero instead of panicking.
+func TestRetryWithNegativeJitter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	backoff := 100 * time.Millisecond
+	timer := &recordingTimer{}
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithBackoff(backoff),
+		wx.WithMaxJitter(-time.Second),
+		wx.WithTimer(timer),
+	)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	for i, d := range timer.Delays() {
+		if d != backoff {
+			t.Errorf("Expected delay %d to be %v without jitter, got %v", i, backoff, d)
+		}
+	}
+}
+
+// TestRetryOnTruncatedErrorBody verifies that an error body cut short by a dropped connection
+// is retried even when retryIf only accepts watsonx errors.
+func TestRetryOnTruncatedErrorBody(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Promise more bytes than are sent, then drop the connection
+			w.Header().Set("Content-Length", "100")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"errors":`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var retryErrors []error
+
+	resp, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithBackoff(0),
+		wx.WithNoJitter(),
+		wx.WithRetryIf(func(err error) bool {
+			var wxErr *wx.WatsonxError
+			return errors.As(err, &wxErr)
+		}),
+		wx.WithOnRetry(func(attempt uint, err error) {
+			retryErrors = append(retryErrors, err)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected the truncated response to be retried, got %v", err)
+	}
+	resp.Body.Close()
+
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+
+	if len(retryErrors) != 1 || !errors.Is(retryErrors[0], io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected one retry caused by io.ErrUnexpectedEOF, got %v", retryErrors)
+	}
+
+	if !strings.Contains(retryErrors[0].Error(), "attempt 1") {
+		t.Errorf("Expected the error to name the failed attempt, got %q", retryErrors[0])
+	}
+}
+
+func TestRetryLinearBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	timer := &recordingTimer{}
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithRetries(3),
+		wx.WithLinearBackoff(time.Second),
+		wx.WithNoJitter(),
+		wx.WithTimer(timer),
+	)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if !reflect.DeepEqual(timer.Delays(), expected) {
+		t.Errorf("Expected delays %v, got %v", expected, timer.Delays())
+	}
+}
+
+func TestRetryResponseValidator(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first response is a 200 with an empty body
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+	}))
+	defer server.Close()
+
+	errEmptyBody := errors.New("empty body")
+	var retryErrors []error
+
+	resp, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithBackoff(0),
+		wx.WithNoJitter(),
+		wx.WithOnRetry(func(attempt uint, err error) {
+			retryErrors = append(retryErrors, err)
+		}),
+		wx.WithResponseValidator(func(resp *http.Response) error {
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			if len(body) == 0 {
+				return errEmptyBody
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	var invalidErr *wx.InvalidResponseError
+	if len(retryErrors) != 1 || !errors.As(retryErrors[0], &invalidErr) || !errors.Is(retryErrors[0], errEmptyBody) {
+		t.Fatalf("Expected one retry caused by an InvalidResponseError, got %v", retryErrors)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"results":[{"generated_text":"ok"}]}` {
+		t.Errorf("Expected the validated body to still be readable, got %q", body)
+	}
+}
+
+// fakeClock is a Clock and a Timer whose time only moves forward when a delay is waited on
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	ch <- now
+	return ch
+}
+
+// TestRetryMaxElapsedTime validates that retries stop once the next attempt would start past the max elapsed time
+func TestRetryMaxElapsedTime(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	attempts := 0
+	lastErr := io.ErrUnexpectedEOF
+
+	start := time.Now()
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			attempts++
+			return nil, lastErr
+		},
+		wx.WithRetries(10),
+		wx.WithBackoff(10*time.Second),
+		wx.WithNoJitter(),
+		wx.WithMaxElapsedTime(35*time.Second),
+		wx.WithClock(clock),
+		wx.WithTimer(clock),
+	)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the fake clock to complete instantly, took %v", elapsed)
+	}
+
+	// attempts start at 0s, 10s, 20s and 30s; the next one would start at 40s
+	if attempts != 4 {
+		t.Errorf("Expected 4 attempts, got %d", attempts)
+	}
+
+	if !errors.Is(err, wx.ErrMaxElapsedTime) {
+		t.Fatalf("Expected ErrMaxElapsedTime, got %v", err)
+	}
+
+	var timeoutErr *wx.RetryTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected a RetryTimeoutError, got %T", err)
+	}
+	if timeoutErr.Attempts != 4 || timeoutErr.LastErr != lastErr {
+		t.Errorf("Expected 4 attempts and the last error, got %d and %v", timeoutErr.Attempts, timeoutErr.LastErr)
+	}
+}
+
+// TestRetryAttemptCount validates that the returned error reports how many attempts were made
+func TestRetryAttemptCount(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var onRetryCalls uint
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithRetries(5),
+		wx.WithBackoff(time.Millisecond),
+		wx.WithNoJitter(),
+		wx.WithRetryIf(func(err error) bool {
+			var wxErr *wx.WatsonxError
+			return errors.As(err, &wxErr) && wxErr.StatusCode == http.StatusServiceUnavailable
+		}),
+		wx.WithOnRetry(func(n uint, err error) {
+			onRetryCalls++
+		}),
+	)
+
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) {
+		t.Fatalf("Expected a WatsonxError, got %v", err)
+	}
+
+	if wxErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected the final 400 error, got %d", wxErr.StatusCode)
+	}
+
+	// two retried 503 responses, then the 400 that is not retried
+	if wxErr.AttemptCount != onRetryCalls+1 || wxErr.AttemptCount != 3 {
+		t.Errorf("Expected 3 attempts for %d retries, got %d", onRetryCalls, wxErr.AttemptCount)
+	}
+}
+
+// TestRetryAttemptCountExhausted validates the attempt count when every attempt fails
+func TestRetryAttemptCountExhausted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithRetries(4),
+		wx.WithBackoff(time.Millisecond),
+		wx.WithNoJitter(),
+	)
+
+	var wxErr *wx.WatsonxError
+	if !errors.As(err, &wxErr) {
+		t.Fatalf("Expected a WatsonxError, got %v", err)
+	}
+
+	if wxErr.AttemptCount != 4 {
+		t.Errorf("Expected 4 attempts, got %d", wxErr.AttemptCount)
+	}
+}
+
+// TestRetryWithRawResponses validates that the final failed response is returned with its body intact
+func TestRetryWithRawResponses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors":[{"code":"invalid_input","message":"bad prompt"}],"custom":"detail"}`))
+	}))
+	defer server.Close()
+
+	resp, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithRetries(3),
+		wx.WithBackoff(time.Millisecond),
+		wx.WithNoJitter(),
+		wx.WithRawResponses(),
+	)
+	if err != nil {
+		t.Fatalf("Expected the raw response instead of an error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if calls != 2 {
+		t.Errorf("Expected the 503 to be retried before the final 400, got %d calls", calls)
+	}
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected the final 400 response, got %d", resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `"custom":"detail"`) {
+		t.Errorf("Expected the raw body to be readable, got %q", body)
+	}
+}
+
+// TestRetryWithRawResponsesOversizedBody validates that a raw response keeps the part of the body past the error body limit
+func TestRetryWithRawResponsesOversizedBody(t *testing.T) {
+	large := strings.Repeat("x", 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(large))
+	}))
+	defer server.Close()
+
+	resp, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithRetries(1),
+		wx.WithMaxErrorBodySize(10),
+		wx.WithRawResponses(),
+	)
+	if err != nil {
+		t.Fatalf("Expected the raw response instead of an error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != large {
+		t.Errorf("Expected the whole body, got %d bytes", len(body))
+	}
+}
+
+// TestRetryJitterFactor validates that a jitter factor scales with each attempt's exponential backoff
+func TestRetryJitterFactor(t *testing.T) {
+	const base = 100 * time.Millisecond
+	const attempts = 6
+
+	sawJitter := make([]bool, attempts)
+	for seed := int64(0); seed < 20; seed++ {
+		timer := &recordingTimer{}
+		wx.Retry(
+			func() (*http.Response, error) {
+				return nil, io.ErrUnexpectedEOF
+			},
+			wx.WithRetries(attempts),
+			wx.WithExponentialBackoff(base),
+			wx.WithMaxJitter(time.Second),
+			wx.WithJitterFactor(0.2),
+			wx.WithRandSource(rand.New(rand.NewSource(seed))),
+			wx.WithTimer(timer),
+		)
+
+		delays := timer.Delays()
+		if len(delays) != attempts {
+			t.Fatalf("Expected %d delays, got %v", attempts, delays)
+		}
+
+		for n, delay := range delays {
+			expected := base << n
+			spread := expected / 5
+			if delay < expected-spread || delay > expected+spread {
+				t.Fatalf("Attempt %d: expected a delay within 20%% of %v, got %v", n+1, expected, delay)
+			}
+			if delay != expected {
+				sawJitter[n] = true
+			}
+		}
+	}
+
+	for n, jittered := range sawJitter {
+		if !jittered {
+			t.Errorf("Attempt %d: expected the delay to be jittered", n+1)
+		}
+	}
+}
+
+// TestRetryJitterPrecedence validates that the last of WithMaxJitter and WithJitterFactor wins
+func TestRetryJitterPrecedence(t *testing.T) {
+	timer := &recordingTimer{}
+	wx.Retry(
+		func() (*http.Response, error) {
+			return nil, io.ErrUnexpectedEOF
+		},
+		wx.WithRetries(1),
+		wx.WithBackoff(time.Second),
+		wx.WithJitterFactor(0.5),
+		wx.WithMaxJitter(time.Millisecond),
+		wx.WithTimer(timer),
+	)
+
+	delays := timer.Delays()
+	if len(delays) != 1 || delays[0] < time.Second || delays[0] >= time.Second+time.Millisecond {
+		t.Errorf("Expected the absolute jitter to replace the factor, got %v", delays)
+	}
+}
+
+// TestRetryOnIncompleteJSON validates that a 200 response with a truncated JSON body is retried when opted in
+func TestRetryOnIncompleteJSON(t *testing.T) {
+	for _, retry := range []bool{false, true} {
+		var calls int32
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_te`))
+				return
+			}
+			w.Write([]byte(`{"model_id": "mock-model", "results": [{"generated_text": "hello"}]}`))
+		}
+
+		retryOptions := []wx.RetryOption{wx.WithRetries(2), wx.WithBackoff(0), wx.WithNoJitter()}
+		if retry {
+			retryOptions = append(retryOptions, wx.WithRetryOnIncompleteJSON())
+		}
+		client := newMockClientWithHttpOptions(t, handler, []wx.HttpClientOption{wx.WithRetryOptions(retryOptions...)})
+
+		text, err := client.Complete(context.Background(), "mock-model", "Say hello")
+		if !retry {
+			if err == nil {
+				t.Error("Expected a decode error without the option, got nil")
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected the truncated response to be retried, got %v", err)
+		}
+		if text != "hello" {
+			t.Errorf("Expected the complete response, got %q", text)
+		}
+		if got := atomic.LoadInt32(&calls); got != 2 {
+			t.Errorf("Expected one retry, got %d calls", got)
+		}
+	}
+}
+
+// TestRetryMinBackoff validates that every delay is raised to the floor
+func TestRetryMinBackoff(t *testing.T) {
+	timer := &recordingTimer{}
+	wx.Retry(
+		func() (*http.Response, error) {
+			return nil, io.ErrUnexpectedEOF
+		},
+		wx.WithRetries(5),
+		wx.WithExponentialBackoff(time.Millisecond),
+		wx.WithJitterFactor(1),
+		wx.WithRandSource(rand.New(rand.NewSource(1))),
+		wx.WithMinBackoff(10*time.Millisecond),
+		wx.WithTimer(timer),
+	)
+
+	delays := timer.Delays()
+	if len(delays) != 5 {
+		t.Fatalf("Expected 5 delays, got %v", delays)
+	}
+	for i, delay := range delays {
+		if delay < 10*time.Millisecond {
+			t.Errorf("Delay %d: expected at least 10ms, got %v", i+1, delay)
+		}
+	}
+	// 1ms * 2^4 with up to 100% jitter can exceed the floor, which is then left as is
+	if delays[0] != 10*time.Millisecond {
+		t.Errorf("Expected the first delay to be raised to the floor, got %v", delays[0])
+	}
+}
+
+func TestRetryBackoffFunc(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	timer := &recordingTimer{}
+	var attempts []uint
+
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			return http.Get(server.URL)
+		},
+		wx.WithRetries(4),
+		wx.WithExponentialBackoff(time.Second),
+		wx.WithMinBackoff(time.Minute),
+		wx.WithBackoffFunc(func(attempt uint, err error) time.Duration {
+			attempts = append(attempts, attempt)
+			if err == nil {
+				t.Error("Expected the error of the failed attempt")
+			}
+			return time.Duration(attempt*attempt) * 10 * time.Millisecond
+		}),
+		wx.WithTimer(timer),
+	)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	expected := []time.Duration{10 * time.Millisecond, 40 * time.Millisecond, 90 * time.Millisecond, 160 * time.Millisecond}
+	if !reflect.DeepEqual(timer.Delays(), expected) {
+		t.Errorf("Expected delays %v, got %v", expected, timer.Delays())
+	}
+	if !reflect.DeepEqual(attempts, []uint{1, 2, 3, 4}) {
+		t.Errorf("Expected attempts 1 to 4, got %v", attempts)
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+type exchangedToken struct {
+	Value   string
+	Expires int64
+}
+
+func TestRetryValue(t *testing.T) {
+	timer := &recordingTimer{}
+	calls := 0
+
+	token, err := wx.RetryValue(func() (exchangedToken, error) {
+		calls++
+		if calls < 3 {
+			return exchangedToken{}, io.ErrUnexpectedEOF
+		}
+		return exchangedToken{Value: "token", Expires: 3600}, nil
+	}, nil,
+		wx.WithRetries(5),
+		wx.WithExponentialBackoff(100*time.Millisecond),
+		wx.WithNoJitter(),
+		wx.WithTimer(timer),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token != (exchangedToken{Value: "token", Expires: 3600}) {
+		t.Errorf("Expected the value of the successful call, got %+v", token)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+
+	// The same exponential backoff as Retry
+	delays := timer.Delays()
+	if len(delays) != 2 || delays[0] != 100*time.Millisecond || delays[1] != 200*time.Millisecond {
+		t.Errorf("Expected delays of 100ms and 200ms, got %v", delays)
+	}
+}
+
+func TestRetryValueMatchesRetry(t *testing.T) {
+	failure := errors.New("still failing")
+
+	valueTimer := &recordingTimer{}
+	valueCalls := 0
+	value, valueErr := wx.RetryValue(func() (exchangedToken, error) {
+		valueCalls++
+		return exchangedToken{Value: "partial"}, failure
+	}, func(error) bool { return true },
+		wx.WithRetries(3), wx.WithBackoff(time.Second), wx.WithNoJitter(), wx.WithTimer(valueTimer))
+
+	httpTimer := &recordingTimer{}
+	httpCalls := 0
+	_, httpErr := wx.Retry(func() (*http.Response, error) {
+		httpCalls++
+		return nil, failure
+	}, wx.WithRetryIf(func(error) bool { return true }),
+		wx.WithRetries(3), wx.WithBackoff(time.Second), wx.WithNoJitter(), wx.WithTimer(httpTimer))
+
+	if !errors.Is(valueErr, failure) || !errors.Is(httpErr, failure) {
+		t.Fatalf("Expected both to return the last error, got %v and %v", valueErr, httpErr)
+	}
+	if value != (exchangedToken{}) {
+		t.Errorf("Expected the zero value on failure, got %+v", value)
+	}
+	if valueCalls != httpCalls {
+		t.Errorf("Expected the same number of attempts, got %d and %d", valueCalls, httpCalls)
+	}
+	if len(valueTimer.Delays()) != len(httpTimer.Delays()) {
+		t.Errorf("Expected the same delays, got %v and %v", valueTimer.Delays(), httpTimer.Delays())
+	}
+
+	// retryIf stops the retries
+	calls := 0
+	_, err := wx.RetryValue(func() (int, error) {
+		calls++
+		return 0, failure
+	}, func(error) bool { return false }, wx.WithRetries(3), wx.WithBackoff(0))
+	if !errors.Is(err, failure) || calls != 1 {
+		t.Errorf("Expected a single attempt, got %d calls and %v", calls, err)
+	}
+}
+
+func TestRetryValueRetriesPlainErrorsByDefault(t *testing.T) {
+	calls := 0
+	value, err := wx.RetryValue(func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("token exchange failed")
+		}
+		return "token", nil
+	}, nil, wx.WithRetries(3), wx.WithBackoff(0), wx.WithNoJitter(), wx.WithTimer(&recordingTimer{}))
+	if err != nil || value != "token" {
+		t.Fatalf("Expected the plain error to be retried until success, got %q and %v", value, err)
+	}
+
+	// A condition set with WithRetryIf still takes precedence
+	calls = 0
+	_, err = wx.RetryValue(func() (string, error) {
+		calls++
+		return "", errors.New("token exchange failed")
+	}, nil, wx.WithRetries(3), wx.WithRetryIf(func(error) bool { return false }))
+	if err == nil || calls != 1 {
+		t.Errorf("Expected a single attempt with WithRetryIf, got %d calls and %v", calls, err)
+	}
+}
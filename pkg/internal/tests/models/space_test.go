@@ -0,0 +1,80 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestSpaceIDInRequestBodies(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string]map[string]interface{}{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case wx.GenerateTextEndpoint:
+			w.Write([]byte(`{"results":[{"generated_text":"ok"}]}`))
+		case wx.EmbeddingEndpoint:
+			w.Write([]byte(`{"results":[{"embedding":[0.1,0.2]}]}`))
+		case wx.ChatEndpoint:
+			w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, wx.WithWatsonxProjectID(""), wx.WithWatsonxSpaceID("mock-space-id"))
+
+	if _, err := client.GenerateText("model", "prompt"); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if _, err := client.EmbedQuery("model", "text"); err != nil {
+		t.Fatalf("EmbedQuery failed: %v", err)
+	}
+	if _, err := client.SimpleChat("model", "hi"); err != nil {
+		t.Fatalf("SimpleChat failed: %v", err)
+	}
+
+	for _, endpoint := range []string{wx.GenerateTextEndpoint, wx.EmbeddingEndpoint, wx.ChatEndpoint} {
+		body := bodies[endpoint]
+		if body["space_id"] != "mock-space-id" {
+			t.Errorf("Expected space_id on %s, got %v", endpoint, body["space_id"])
+		}
+		if _, ok := body["project_id"]; ok {
+			t.Errorf("Expected no project_id on %s, got %v", endpoint, body["project_id"])
+		}
+	}
+}
+
+func TestSpaceIDAndProjectIDAreExclusive(t *testing.T) {
+	_, err := wx.NewClient(
+		wx.WithURL("us-south.ml.cloud.ibm.com"),
+		wx.WithWatsonxAPIKey("mock-api-key"),
+		wx.WithWatsonxProjectID("mock-project-id"),
+		wx.WithWatsonxSpaceID("mock-space-id"),
+	)
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("Expected a mutually exclusive error, got %v", err)
+	}
+}
+
+func TestSpaceIDOrProjectIDRequired(t *testing.T) {
+	t.Setenv(wx.WatsonxProjectIDEnvVarName, "")
+
+	_, err := wx.NewClient(
+		wx.WithURL("us-south.ml.cloud.ibm.com"),
+		wx.WithWatsonxAPIKey("mock-api-key"),
+	)
+	if err == nil {
+		t.Fatalf("Expected an error when neither a project nor a space ID is set")
+	}
+}
@@ -1,9 +1,15 @@
 package test
 
 import (
-	wx "github.com/IBM/watsonx-go/pkg/models"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
 )
 
 func getClient(t *testing.T) *wx.Client {
@@ -26,3 +32,53 @@ func getClient(t *testing.T) *wx.Client {
 
 	return client
 }
+
+// newMockServer starts a TLS server that serves IAM tokens and delegates every other request to handler.
+func newMockServer(t testing.TB, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == wx.TokenPath {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token":"mock-token","expiration":%d}`, time.Now().Add(time.Hour).Unix())
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newMockClient creates a client whose IAM and watsonx endpoints are served by handler.
+// Requests are not retried.
+func newMockClient(t testing.TB, handler http.HandlerFunc, options ...wx.ClientOption) *wx.Client {
+	return newMockClientWithHttpOptions(t, handler, nil, options...)
+}
+
+// newMockClientWithHttpOptions creates a mock client whose HttpClient is configured with httpOptions,
+// which can override the default of not retrying requests.
+func newMockClientWithHttpOptions(t testing.TB, handler http.HandlerFunc, httpOptions []wx.HttpClientOption, options ...wx.ClientOption) *wx.Client {
+	server := newMockServer(t, handler)
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	httpClient := wx.NewHttpClient(
+		append([]wx.HttpClientOption{
+			wx.WithTransport(server.Client().Transport),
+			wx.WithRetryOptions(wx.WithRetries(1)),
+		}, httpOptions...)...,
+	)
+
+	client, err := wx.NewClient(
+		append([]wx.ClientOption{
+			wx.WithURL(host),
+			wx.WithIAM(host),
+			wx.WithWatsonxAPIKey("mock-api-key"),
+			wx.WithWatsonxProjectID("mock-project-id"),
+			wx.WithHttpClient(httpClient),
+		}, options...)...,
+	)
+	if err != nil {
+		t.Fatalf("Failed to create mock client for testing. Error: %v", err)
+	}
+
+	return client
+}
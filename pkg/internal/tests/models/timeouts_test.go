@@ -0,0 +1,93 @@
+package test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// TestTLSHandshakeTimeoutFailsFast connects to a server that never completes the TLS handshake.
+func TestTLSHandshakeTimeoutFailsFast(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			// Hold the connection open without answering the handshake
+			defer conn.Close()
+		}
+	}()
+
+	client := wx.NewHttpClient(
+		wx.WithDialTimeout(time.Second),
+		wx.WithTLSHandshakeTimeout(100*time.Millisecond),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "https://"+listener.Addr().String(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	start := time.Now()
+	_, err = client.Do(req)
+	if err == nil {
+		t.Fatalf("Expected the TLS handshake to time out")
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the request to fail fast, took %v", elapsed)
+	}
+}
+
+// TestResponseHeaderTimeoutKeepsStreamOpen streams a body for longer than the response header timeout.
+func TestResponseHeaderTimeoutKeepsStreamOpen(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		for i := 0; i < 5; i++ {
+			time.Sleep(60 * time.Millisecond)
+			w.Write([]byte("chunk\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	client := wx.NewHttpClient(
+		wx.WithTransport(server.Client().Transport),
+		wx.WithResponseHeaderTimeout(100*time.Millisecond),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Expected the stream to be read to the end, got %v", err)
+	}
+
+	if got := strings.Count(string(body), "chunk"); got != 5 {
+		t.Errorf("Expected 5 chunks, got %d", got)
+	}
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// wordTokenizer counts whitespace-separated words as tokens
+type wordTokenizer struct {
+	calls int
+}
+
+func (t *wordTokenizer) CountTokens(text string) (int, error) {
+	t.calls++
+	return len(strings.Fields(text)), nil
+}
+
+func TestCountInputTokensWithLocalTokenizer(t *testing.T) {
+	tokenizer := &wordTokenizer{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s", r.URL.Path)
+	}, wx.WithTokenizer(tokenizer))
+
+	count, err := client.CountInputTokens(context.Background(), "ibm/granite-13b-instruct-v2", "one two three")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 tokens, got %d", count)
+	}
+	if tokenizer.calls != 1 {
+		t.Errorf("Expected the tokenizer to be called once, got %d", tokenizer.calls)
+	}
+}
+
+func TestCountInputTokensWithModelTokenizer(t *testing.T) {
+	fallback := &wordTokenizer{}
+	granite := &wordTokenizer{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s", r.URL.Path)
+	}, wx.WithTokenizer(fallback), wx.WithModelTokenizer("ibm/granite-13b-instruct-v2", granite))
+
+	if _, err := client.CountInputTokens(context.Background(), "ibm/granite-13b-instruct-v2", "one two"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.CountInputTokens(context.Background(), "meta-llama/llama-3-3-70b-instruct", "one two"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if granite.calls != 1 || fallback.calls != 1 {
+		t.Errorf("Expected one call to each tokenizer, got %d for the model and %d for the fallback", granite.calls, fallback.calls)
+	}
+}
+
+func TestCountInputTokensWithServer(t *testing.T) {
+	var payload map[string]interface{}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wx.TokenizeEndpoint {
+			t.Errorf("Expected path %s, got %s", wx.TokenizeEndpoint, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"model_id":"ibm/granite-13b-instruct-v2","result":{"token_count":4,"tokens":["one","two","thr","ee"]}}`))
+	})
+
+	count, err := client.CountInputTokens(context.Background(), "ibm/granite-13b-instruct-v2", "one two three")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 tokens, got %d", count)
+	}
+	if payload["input"] != "one two three" || payload["model_id"] != "ibm/granite-13b-instruct-v2" {
+		t.Errorf("Unexpected tokenization payload %v", payload)
+	}
+
+	serverCount, err := client.ServerTokenizer("ibm/granite-13b-instruct-v2").CountTokens("one two three")
+	if err != nil || serverCount != 4 {
+		t.Errorf("Expected 4 tokens from the server tokenizer, got %d, %v", serverCount, err)
+	}
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestChatToolSchemaValidation(t *testing.T) {
+	weather := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"city":  map[string]interface{}{"type": "string"},
+			"units": map[string]interface{}{"type": "string", "enum": []string{"celsius", "fahrenheit"}},
+		},
+		"required": []string{"city"},
+	}
+
+	tests := []struct {
+		name  string
+		tools []wx.ChatTool
+		valid bool
+	}{
+		{"valid", []wx.ChatTool{wx.CreateFunction("get_weather", "Current weather", weather)}, true},
+		{"raw JSON schema", []wx.ChatTool{wx.CreateFunction("get_time", "Current time", json.RawMessage(`{"type":"object","properties":{}}`))}, true},
+		{"no parameters", []wx.ChatTool{wx.CreateFunction("ping", "Ping", nil)}, true},
+		{"missing name", []wx.ChatTool{wx.CreateFunction("", "No name", weather)}, false},
+		{"invalid name", []wx.ChatTool{wx.CreateFunction("get weather", "Space in name", weather)}, false},
+		{"duplicate name", []wx.ChatTool{wx.CreateFunction("ping", "", nil), wx.CreateFunction("ping", "", nil)}, false},
+		{"malformed JSON", []wx.ChatTool{wx.CreateFunction("broken", "", json.RawMessage(`{"type": "object",`))}, false},
+		{"not an object", []wx.ChatTool{wx.CreateFunction("broken", "", []string{"city"})}, false},
+		{"unknown type", []wx.ChatTool{wx.CreateFunction("broken", "", map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"city": map[string]interface{}{"type": "text"}},
+		})}, false},
+		{"undefined required property", []wx.ChatTool{wx.CreateFunction("broken", "", map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+			"required":   []string{"country"},
+		})}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Write([]byte(`{"id": "chat-1", "model_id": "mock-model", "choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}]}`))
+			})
+
+			_, err := client.Chat("mock-model", []wx.ChatMessage{wx.CreateUserMessage("hello")}, wx.WithChatTools(tt.tools...))
+
+			if tt.valid {
+				if err != nil {
+					t.Fatalf("Expected a valid tool to pass through, got %v", err)
+				}
+				if atomic.LoadInt32(&calls) != 1 {
+					t.Errorf("Expected the request to be sent")
+				}
+				return
+			}
+
+			var requestErr *wx.RequestError
+			if !errors.As(err, &requestErr) {
+				t.Fatalf("Expected a *RequestError, got %T: %v", err, err)
+			}
+			if got := atomic.LoadInt32(&calls); got != 0 {
+				t.Errorf("Expected no HTTP call for an invalid tool, got %d", got)
+			}
+		})
+	}
+}
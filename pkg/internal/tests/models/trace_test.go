@@ -0,0 +1,69 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestConnectionTrace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var traces []wx.ConnectionTrace
+
+	client := wx.NewHttpClient(
+		wx.WithTransport(server.Client().Transport),
+		wx.WithConnectionTrace(func(req *http.Request, trace wx.ConnectionTrace) {
+			mu.Lock()
+			defer mu.Unlock()
+			traces = append(traces, trace)
+		}),
+	)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+
+		resp, err := client.DoWithRetry(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	if len(traces) != 2 {
+		t.Fatalf("Expected 2 traces, got %d", len(traces))
+	}
+
+	first, second := traces[0], traces[1]
+
+	if first.ConnReused {
+		t.Errorf("Expected the first request to open a new connection")
+	}
+	if first.TLSHandshake <= 0 {
+		t.Errorf("Expected the first request to record a TLS handshake, got %v", first.TLSHandshake)
+	}
+
+	if !second.ConnReused {
+		t.Errorf("Expected the second request to reuse the connection")
+	}
+
+	for i, trace := range traces {
+		if trace.TimeToFirstByte < 20*time.Millisecond {
+			t.Errorf("Expected trace %d to record the server delay in TTFB, got %v", i, trace.TimeToFirstByte)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// newHTTP2Server starts a TLS server that supports HTTP/2 and returns a plain transport trusting it
+func newHTTP2Server(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *http.Transport) {
+	server := httptest.NewUnstartedServer(handler)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	return server, &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+}
+
+func TestWithHTTP2(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		server, transport := newHTTP2Server(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		client := wx.NewHttpClient(wx.WithTransport(transport), wx.WithHTTP2(enabled))
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+
+		expected := 1
+		if enabled {
+			expected = 2
+		}
+		if resp.ProtoMajor != expected {
+			t.Errorf("Expected HTTP/%d with HTTP/2 enabled=%v, got %s", expected, enabled, resp.Proto)
+		}
+	}
+}
+
+func TestWithMaxIdleConnsPerHost(t *testing.T) {
+	const concurrency = 4
+
+	// hold each batch of requests until all of them arrived, so they need separate connections
+	var arrived sync.WaitGroup
+	server, transport := newHTTP2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var dials int32
+	dialer := &net.Dialer{}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	client := wx.NewHttpClient(
+		wx.WithTransport(transport),
+		wx.WithHTTP2(false),
+		wx.WithMaxIdleConnsPerHost(concurrency),
+	)
+
+	for batch := 0; batch < 2; batch++ {
+		arrived.Add(concurrency)
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+				if err != nil {
+					t.Errorf("Failed to create request: %v", err)
+					return
+				}
+
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+					return
+				}
+				resp.Body.Close()
+			}()
+		}
+		wg.Wait()
+	}
+
+	// with the default of 2 idle connections per host, the second batch would dial twice more
+	if got := atomic.LoadInt32(&dials); got != concurrency {
+		t.Errorf("Expected the second batch to reuse all %d idle connections, got %d dials", concurrency, got)
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrAttemptTimeout is matched by the error of an attempt cancelled by WithAttemptTimeout
+var ErrAttemptTimeout = errors.New("attempt timed out")
+
+// attemptFunc is a retryable function that receives the context of the attempt
+type attemptFunc func(ctx context.Context) (*http.Response, error)
+
+// attempt runs a single attempt. With an attempt timeout, the attempt gets its own context,
+// cancelled when the timeout elapses before the function returns. The context of a returned
+// response stays alive until its body is closed, so the body can still be read.
+func (cfg *RetryConfig) attempt(fn attemptFunc) (*http.Response, error) {
+	if cfg.attemptTimeout <= 0 {
+		return fn(cfg.context)
+	}
+
+	ctx, cancel := context.WithCancelCause(cfg.context)
+	returned := make(chan struct{})
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		select {
+		case <-cfg.timer.After(cfg.attemptTimeout):
+			cancel(ErrAttemptTimeout)
+		case <-returned:
+		case <-ctx.Done():
+		}
+	}()
+
+	resp, err := fn(ctx)
+	close(returned)
+	<-watched
+
+	// A cancellation of the call is left to the retry loop, which stops on it
+	if cfg.context.Err() == nil && context.Cause(ctx) == ErrAttemptTimeout {
+		closeResponse(resp)
+		if err == nil {
+			err = ctx.Err()
+		}
+		// The cause is not wrapped so the error is not mistaken for a cancellation of the call
+		return nil, fmt.Errorf("%w after %v: %v", ErrAttemptTimeout, cfg.attemptTimeout, err)
+	}
+
+	if resp == nil {
+		cancel(nil)
+		return nil, err
+	}
+	resp.Body = cancelOnClose{resp.Body, func() { cancel(nil) }}
+	return resp, err
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// GenerateResult holds the outcome of a single generation in a batch.
+// Result is the first result of Response, which holds every result, e.g. one per prompt of Prompts.
+type GenerateResult struct {
+	Result   GenerateTextResult
+	Response GenerateTextResponse
+	Err      error
+}
+
+// GenerateBatch generates text for each request using at most concurrency parallel requests.
+// Each request is generated like Generate does, so tuned models and several prompts are supported.
+// Results are in the same order as reqs and a failing request only sets the Err of its own result.
+// If ctx is cancelled, requests that were not sent yet fail with the context error, which is also returned.
+func (m *Client) GenerateBatch(ctx context.Context, reqs []GenerateTextRequest, concurrency int) ([]GenerateResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]GenerateResult, len(reqs))
+	jobs := make(chan int)
+
+	// Refresh once up front instead of in every worker
+	m.CheckAndRefreshToken()
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(reqs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				response, err := m.Generate(ctx, reqs[i])
+				results[i] = GenerateResult{Response: response, Err: err}
+				if err == nil {
+					results[i].Result = response.Results[0]
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range reqs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			for j := i; j < len(reqs); j++ {
+				results[j].Err = ctx.Err()
+			}
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+// EmbedAllConcurrency is the number of embedding batches EmbedAll sends in parallel
+const EmbedAllConcurrency = 4
+
+// EmbedBatchError reports the batch of an EmbedAll call that failed
+type EmbedBatchError struct {
+	Batch int // Index of the batch
+	Start int // Index of the first input of the batch
+	End   int // Index after the last input of the batch
+	Err   error
+}
+
+func (e *EmbedBatchError) Error() string {
+	return fmt.Sprintf("embedding batch %d (inputs %d-%d) failed: %v", e.Batch, e.Start, e.End-1, e.Err)
+}
+
+func (e *EmbedBatchError) Unwrap() error {
+	return e.Err
+}
+
+// EmbedAll embeds any number of inputs by splitting them into batches of batchSize inputs,
+// sending at most EmbedAllConcurrency batches in parallel.
+// The vectors are in the same order as inputs. If a batch fails, the error of the first failing batch
+// is returned as an *EmbedBatchError.
+func (m *Client) EmbedAll(ctx context.Context, model string, inputs []string, batchSize int, options ...EmbeddingOption) ([][]float64, error) {
+	if batchSize < 1 {
+		return nil, errors.New("batch size must be positive")
+	}
+
+	vectors := make([][]float64, len(inputs))
+	batches := (len(inputs) + batchSize - 1) / batchSize
+	errs := make([]error, batches)
+	jobs := make(chan int)
+
+	// Refresh once up front instead of in every worker
+	m.CheckAndRefreshToken()
+
+	var wg sync.WaitGroup
+	for w := 0; w < EmbedAllConcurrency && w < batches; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				start := batch * batchSize
+				end := min(start+batchSize, len(inputs))
+				errs[batch] = m.embedBatch(ctx, model, inputs[start:end], vectors[start:end], options...)
+			}
+		}()
+	}
+
+dispatch:
+	for batch := 0; batch < batches; batch++ {
+		select {
+		case jobs <- batch:
+		case <-ctx.Done():
+			for b := batch; b < batches; b++ {
+				errs[b] = ctx.Err()
+			}
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for batch, err := range errs {
+		if err != nil {
+			start := batch * batchSize
+			return nil, &EmbedBatchError{Batch: batch, Start: start, End: min(start+batchSize, len(inputs)), Err: err}
+		}
+	}
+
+	return vectors, nil
+}
+
+// embedBatch embeds the inputs into vectors, which must have the same length
+func (m *Client) embedBatch(ctx context.Context, model string, inputs []string, vectors [][]float64, options ...EmbeddingOption) error {
+	payload := m.newEmbeddingPayload(model, inputs, options...)
+	if payload.Model == "" {
+		return &RequestError{Op: "select model", Err: errNoModel}
+	}
+
+	response, err := m.generateEmbeddingRequest(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	if len(response.Results) != len(inputs) {
+		return fmt.Errorf("expected %d embeddings, received %d", len(inputs), len(response.Results))
+	}
+
+	for i, result := range response.Results {
+		vectors[i] = result.Embedding
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	DeploymentJobsEndpoint string = "/ml/v4/deployment_jobs"
+)
+
+// States of a batch deployment job
+const (
+	BatchQueued    = "queued"
+	BatchRunning   = "running"
+	BatchCompleted = "completed"
+	BatchFailed    = "failed"
+	BatchCanceled  = "canceled"
+)
+
+// BatchRequest describes a batch scoring job of a batch deployment.
+// The inputs are read from and the output written to Cloud Object Storage through connection assets.
+type BatchRequest struct {
+	Name         string
+	DeploymentID string
+	Inputs       []COSReference
+	Output       COSReference
+}
+
+type BatchDeployment struct {
+	ID string `json:"id"`
+}
+
+type BatchScoring struct {
+	InputDataReferences []COSReference `json:"input_data_references"`
+	OutputDataReference COSReference   `json:"output_data_reference"`
+}
+
+type BatchPayload struct {
+	ProjectID  string          `json:"project_id,omitempty"`
+	SpaceID    string          `json:"space_id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Deployment BatchDeployment `json:"deployment"`
+	Scoring    BatchScoring    `json:"scoring"`
+}
+
+// BatchJob holds the state of a batch deployment job
+type BatchJob struct {
+	ID        string
+	CreatedAt time.Time
+	Status    string
+	Errors    []ErrorDetail // Set when the job failed
+}
+
+// batchJobResource is the job resource returned by the deployment jobs endpoints
+type batchJobResource struct {
+	Metadata struct {
+		ID        string    `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"metadata"`
+	Entity struct {
+		Scoring struct {
+			Status struct {
+				State   string `json:"state"`
+				Failure *struct {
+					Errors []ErrorDetail `json:"errors"`
+				} `json:"failure,omitempty"`
+			} `json:"status"`
+		} `json:"scoring"`
+	} `json:"entity"`
+}
+
+func (r batchJobResource) job() *BatchJob {
+	job := &BatchJob{
+		ID:        r.Metadata.ID,
+		CreatedAt: r.Metadata.CreatedAt,
+		Status:    r.Entity.Scoring.Status.State,
+	}
+	if failure := r.Entity.Scoring.Status.Failure; failure != nil {
+		job.Errors = failure.Errors
+	}
+	return job
+}
+
+// SubmitBatch submits a batch scoring job to a batch deployment.
+// The job runs asynchronously; use WaitForBatch to wait for it to finish.
+func (m *Client) SubmitBatch(ctx context.Context, req BatchRequest) (*BatchJob, error) {
+	m.CheckAndRefreshToken()
+
+	if err := validateDeploymentID(req.DeploymentID); err != nil {
+		return nil, err
+	}
+
+	if len(req.Inputs) == 0 {
+		return nil, errors.New("batch inputs cannot be empty")
+	}
+	for i, input := range req.Inputs {
+		if err := input.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid batch input %d: %w", i, err)
+		}
+	}
+	if err := req.Output.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid batch output: %w", err)
+	}
+
+	payload := BatchPayload{
+		ProjectID:  m.projectID,
+		SpaceID:    m.spaceID,
+		Name:       req.Name,
+		Deployment: BatchDeployment{ID: req.DeploymentID},
+		Scoring: BatchScoring{
+			InputDataReferences: req.Inputs,
+			OutputDataReference: req.Output,
+		},
+	}
+
+	httpReq, err := m.newJSONRequest(ctx, DeploymentJobsEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.doBatchRequest(httpReq)
+}
+
+// GetBatch returns the current state of a batch deployment job
+func (m *Client) GetBatch(ctx context.Context, id string) (*BatchJob, error) {
+	m.CheckAndRefreshToken()
+
+	if id == "" {
+		return nil, errors.New("batch job ID cannot be empty")
+	}
+
+	httpReq, err := m.newGetRequest(ctx, DeploymentJobsEndpoint+"/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.doBatchRequest(httpReq)
+}
+
+// WaitForBatch polls a batch deployment job until it completes, fails or is canceled.
+// Polls are spaced with the retry backoff options, as in WaitForExtraction.
+func (m *Client) WaitForBatch(ctx context.Context, id string, options ...RetryOption) (*BatchJob, error) {
+	opts := newDefaultRetryConfig()
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	var job *BatchJob
+	fetch := func() (string, error) {
+		var err error
+		job, err = m.GetBatch(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return job.Status, nil
+	}
+
+	terminal := []string{BatchCompleted, BatchFailed, BatchCanceled}
+	if _, err := poll(ctx, fetch, terminal, opts.timer, func(n uint) time.Duration { return opts.delay(n, nil) }); err != nil {
+		return nil, err
+	}
+
+	switch job.Status {
+	case BatchFailed:
+		if len(job.Errors) > 0 {
+			return job, fmt.Errorf("batch job %s failed: %s - %s", id, job.Errors[0].Code, job.Errors[0].Message)
+		}
+		return job, fmt.Errorf("batch job %s failed", id)
+	case BatchCanceled:
+		return job, fmt.Errorf("batch job %s was canceled", id)
+	}
+
+	return job, nil
+}
+
+// doBatchRequest sends a request to the deployment jobs endpoints and decodes the job
+func (m *Client) doBatchRequest(httpReq *http.Request) (*BatchJob, error) {
+	res, err := m.httpClient.DoWithRetry(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+
+	var resource batchJobResource
+	if err := decodeJSONStream(m.codec, res, &resource); err != nil {
+		return nil, err
+	}
+
+	return resource.job(), nil
+}
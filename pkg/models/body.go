@@ -0,0 +1,62 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// maxDrainSize bounds how much of an unread response body is discarded to reuse the connection.
+// Closing the connection is cheaper than reading past this.
+const maxDrainSize = 64 << 10
+
+// drainAndClose discards what is left of a response body, up to maxDrainSize, and closes it
+// so the underlying connection can be reused by the next request.
+func drainAndClose(body io.ReadCloser) error {
+	io.Copy(io.Discard, io.LimitReader(body, maxDrainSize))
+	return body.Close()
+}
+
+// maxPresizedBodySize bounds the buffer allocated upfront from the Content-Length of a response,
+// so that a wrong header cannot trigger a huge allocation. Longer bodies grow the buffer as they are read.
+const maxPresizedBodySize = 32 << 20
+
+// decodeJSONBody reads the whole response body and decodes it into v with the codec.
+// The raw bytes are returned so that fields not mapped by v remain accessible.
+func decodeJSONBody(codec Codec, res *http.Response, v interface{}) (json.RawMessage, error) {
+	raw, err := readBody(res)
+	if err != nil {
+		return nil, err
+	}
+	if err := codec.Unmarshal(raw, v); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// decodeJSONStream decodes the response body into v as it is read when the codec is a StreamDecoder,
+// so that large responses are not buffered whole. Other codecs fall back to decodeJSONBody.
+func decodeJSONStream(codec Codec, res *http.Response, v interface{}) error {
+	decoder, ok := codec.(StreamDecoder)
+	if !ok {
+		_, err := decodeJSONBody(codec, res, v)
+		return err
+	}
+	return decoder.Decode(res.Body, v)
+}
+
+// readBody reads the whole response body into a buffer sized from the Content-Length when it is known.
+// Unlike io.ReadAll, which doubles its buffer as it reads, this keeps a single allocation for large bodies.
+func readBody(res *http.Response) ([]byte, error) {
+	if res.ContentLength <= 0 {
+		return io.ReadAll(res.Body)
+	}
+
+	// ReadFrom grows the buffer unless bytes.MinRead bytes are free to detect the end of the body
+	buf := bytes.NewBuffer(make([]byte, 0, min(res.ContentLength, maxPresizedBodySize)+bytes.MinRead))
+	if _, err := buf.ReadFrom(res.Body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,7 +1,7 @@
 package models
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -187,6 +187,12 @@ type ChatResponse struct {
 	Usage        *ChatUsage     `json:"usage,omitempty"`
 	ModelVersion *string        `json:"model_version,omitempty"`
 	System       *SystemDetails `json:"system,omitempty"`
+
+	// Raw is the full response payload, giving access to fields this package does not map yet
+	Raw json.RawMessage `json:"-"`
+
+	// RateLimit is the rate limit state sent with the response, or nil if the headers were not sent
+	RateLimit *RateLimitInfo `json:"-"`
 }
 
 type ChatChoice struct {
@@ -198,9 +204,9 @@ type ChatChoice struct {
 }
 
 type ChatUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+	TotalTokens      int64 `json:"total_tokens"`
 }
 
 type ChatLogProbs struct {
@@ -223,7 +229,14 @@ type ChatTopLogProb struct {
 
 // SystemDetails represents system information from the response
 type SystemDetails struct {
-	Warnings interface{} `json:"warnings,omitempty"`
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// Warning is a warning sent by watsonx along with a successful response, such as an input truncation or a model deprecation
+type Warning struct {
+	Message  string `json:"message"`
+	ID       string `json:"id,omitempty"`
+	MoreInfo string `json:"more_info,omitempty"`
 }
 
 const ChatMessageTypeText = "text"
@@ -302,8 +315,9 @@ func CreateAssistantMessage(content string) ChatMessage {
 // Chat generates a text chat based on messages and parameters
 func (c *Client) Chat(modelID string, messages []ChatMessage, options ...ChatOption) (ChatResponse, error) {
 	// Validate input
+	modelID = c.modelOrDefault(modelID)
 	if modelID == "" {
-		return ChatResponse{}, errors.New("modelID cannot be empty")
+		return ChatResponse{}, &RequestError{Op: "select model", Err: errNoModel}
 	}
 
 	if len(messages) == 0 {
@@ -318,6 +332,10 @@ func (c *Client) Chat(modelID string, messages []ChatMessage, options ...ChatOpt
 		}
 	}
 
+	if err := validateChatTools(opts.Tools); err != nil {
+		return ChatResponse{}, err
+	}
+
 	// Build the request payload
 	payload := c.BuildChatRequest(modelID, messages, opts)
 
@@ -363,15 +381,11 @@ func (c *Client) SimpleChat(modelID, prompt string, options ...ChatOption) (stri
 	return text, nil
 }
 
-// BuildChatRequest constructs the ChatRequest payload
+// BuildChatRequest constructs the ChatRequest payload, using the default model of the client when modelID is empty
 func (c *Client) BuildChatRequest(modelID string, messages []ChatMessage, opts *ChatOptions) ChatRequest {
-	// Use the project ID from the client (already configured during client creation)
-	projectID := string(c.projectID)
-
 	payload := ChatRequest{
-		ModelID:             modelID,
+		ModelID:             c.modelOrDefault(modelID),
 		Messages:            messages,
-		ProjectID:           &projectID,
 		Tools:               opts.Tools,
 		ToolChoiceOption:    opts.ToolChoiceOption,
 		ToolChoice:          opts.ToolChoice,
@@ -389,9 +403,44 @@ func (c *Client) BuildChatRequest(modelID string, messages []ChatMessage, opts *
 		TimeLimit:           opts.TimeLimit,
 	}
 
+	// Use the project or space ID from the client (already configured during client creation)
+	if c.spaceID != "" {
+		spaceID := string(c.spaceID)
+		payload.SpaceID = &spaceID
+	} else {
+		projectID := string(c.projectID)
+		payload.ProjectID = &projectID
+	}
+
 	return payload
 }
 
+// BuildChatHTTPRequest builds the fully-formed chat request (URL, headers and body) without sending it,
+// so it can be inspected or snapshotted
+func (c *Client) BuildChatHTTPRequest(modelID string, messages []ChatMessage, options ...ChatOption) (*http.Request, error) {
+	if err := c.CheckAndRefreshToken(); err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	opts := &ChatOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	if err := validateChatTools(opts.Tools); err != nil {
+		return nil, err
+	}
+
+	payload := c.BuildChatRequest(modelID, messages, opts)
+	if payload.ModelID == "" {
+		return nil, &RequestError{Op: "select model", Err: errNoModel}
+	}
+
+	return c.newJSONRequest(context.Background(), ChatEndpoint, payload)
+}
+
 // generateChatRequest sends a request to the chat endpoint
 func (c *Client) generateChatRequest(payload ChatRequest) (ChatResponse, error) {
 	// Ensure we have a valid token
@@ -400,32 +449,19 @@ func (c *Client) generateChatRequest(payload ChatRequest) (ChatResponse, error)
 		return ChatResponse{}, fmt.Errorf("failed to refresh token: %w", err)
 	}
 
-	// Build the URL using the client's configuration
-	chatURL := c.generateUrlFromEndpoint(ChatEndpoint)
-
-	// Marshal the payload to JSON
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		return ChatResponse{}, fmt.Errorf("failed to marshal request payload: %w", err)
-	}
-
-	// Create the HTTP request
-	req, err := http.NewRequest(http.MethodPost, chatURL, bytes.NewReader(payloadJSON))
+	// Create the HTTP request with the JSON payload and required headers
+	req, err := c.newJSONRequest(context.Background(), ChatEndpoint, payload)
 	if err != nil {
 		return ChatResponse{}, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
-	// Set required headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.token.value)
-
 	// Execute the request using the client's HTTP client with retry
 	res, err := c.httpClient.DoWithRetry(req)
 	if err != nil {
 		return ChatResponse{}, err
 	}
 	defer func() {
-		if cerr := res.Body.Close(); cerr != nil {
+		if cerr := drainAndClose(res.Body); cerr != nil {
 			log.Println("error closing response body: ", cerr)
 		}
 	}()
@@ -438,9 +474,13 @@ func (c *Client) generateChatRequest(payload ChatRequest) (ChatResponse, error)
 
 	// Decode the response
 	var chatRes ChatResponse
-	if err := json.NewDecoder(res.Body).Decode(&chatRes); err != nil {
+	raw, err := decodeJSONBody(c.codec, res, &chatRes)
+	if err != nil {
 		return ChatResponse{}, fmt.Errorf("failed to decode response: %w", err)
 	}
+	chatRes.Raw = raw
+	chatRes.RateLimit = ParseRateLimitInfo(res.Header)
+	c.logWarnings(chatRes.System)
 
 	return chatRes, nil
 }
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when the circuit breaker short-circuits a request
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState represents the current state of a CircuitBreaker
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Requests flow normally
+	CircuitOpen                         // Requests are rejected with ErrCircuitOpen
+	CircuitHalfOpen                     // A single probe request is allowed through
+)
+
+// CircuitBreaker stops sending requests after consecutive failures and probes
+// again once the reset timeout has elapsed.
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	failureThreshold int
+	resetTimeout     time.Duration
+
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a closed circuit breaker that opens after failureThreshold
+// consecutive failures and allows a probe request after resetTimeout.
+func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		resetTimeout:     resetTimeout,
+		state:            CircuitClosed,
+	}
+}
+
+// State returns the current state of the circuit breaker
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// Allow reports whether a request may be sent; it returns ErrCircuitOpen if not.
+// Once the reset timeout has elapsed, an open breaker moves to half-open and lets a single probe through.
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.resetTimeout {
+			return ErrCircuitOpen
+		}
+		cb.state = CircuitHalfOpen
+		cb.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if cb.probing {
+			return ErrCircuitOpen
+		}
+		cb.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// RecordSuccess closes the breaker and resets the failure count
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = CircuitClosed
+	cb.failures = 0
+	cb.probing = false
+}
+
+// RecordFailure counts a failure and opens the breaker when the threshold is reached
+// or when a half-open probe fails.
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+	cb.failures++
+
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.state = CircuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// record updates the breaker based on the outcome of a request.
+// Client errors (4xx other than 429) and cancellations do not indicate an unhealthy service and are ignored,
+// apart from releasing a half-open probe. A failed response returned without an error, as with WithRawResponses,
+// is recorded by its status.
+func (cb *CircuitBreaker) record(resp *http.Response, err error) {
+	if err == nil && resp != nil && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		err = &WatsonxError{StatusCode: resp.StatusCode}
+	}
+
+	if err == nil {
+		cb.RecordSuccess()
+		return
+	}
+
+	if isCircuitFailure(err) {
+		cb.RecordFailure()
+		return
+	}
+
+	cb.mu.Lock()
+	cb.probing = false
+	cb.mu.Unlock()
+}
+
+// isCircuitFailure reports whether err indicates the service is unavailable
+func isCircuitFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var wxErr *WatsonxError
+	if errors.As(err, &wxErr) {
+		return wxErr.StatusCode >= 500 || wxErr.StatusCode == 429
+	}
+
+	return true
+}
@@ -1,10 +1,18 @@
 package models
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -13,15 +21,29 @@ const (
 
 type Client struct {
 	url        string
+	baseURL    *url.URL
 	iam        string
 	region     IBMCloudRegion
 	apiVersion string
 
+	tokenMu   sync.RWMutex // guards token, which is refreshed while requests are sent concurrently
 	token     IAMToken
 	apiKey    WatsonxAPIKey
 	projectID WatsonxProjectID
+	spaceID   WatsonxSpaceID
 
-	httpClient Doer
+	httpClient     Doer
+	defaultHeaders http.Header
+	userAgent      string
+
+	maxStreamEventSize int
+	clock              Clock
+	codec              Codec
+	warningLogger      *log.Logger // nil when warnings are not logged
+	modelSpecs         *modelSpecCache
+	defaultModel       string
+	tokenizer          Tokenizer // nil when tokens are counted by watsonx
+	modelTokenizers    map[string]Tokenizer
 }
 
 func NewClient(options ...ClientOption) (*Client, error) {
@@ -33,9 +55,30 @@ func NewClient(options ...ClientOption) (*Client, error) {
 		}
 	}
 
-	if opts.URL == "" {
+	if opts.URL == "" && opts.BaseURL == "" {
 		// User did not specify a URL, build it from the region
-		opts.URL = buildBaseURL(opts.Region)
+		host, err := RegionHost(opts.Region)
+		if err != nil {
+			return nil, err
+		}
+		opts.URL = host
+	}
+
+	if opts.APIVersion == "" {
+		opts.APIVersion = DefaultAPIVersion
+	}
+
+	if err := validateAPIVersion(opts.APIVersion); err != nil {
+		return nil, err
+	}
+
+	var baseURL *url.URL
+	if opts.BaseURL != "" {
+		var err error
+		baseURL, err = parseBaseURL(opts.BaseURL)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if opts.IAM == "" {
@@ -43,16 +86,38 @@ func NewClient(options ...ClientOption) (*Client, error) {
 		opts.IAM = IAMCloudHost
 	}
 
+	if opts.HttpClient == nil {
+		opts.HttpClient = NewHttpClient()
+	}
+
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+
+	if opts.Codec == nil {
+		opts.Codec = jsonCodec{}
+	}
+
 	if opts.apiKey == "" {
 		return nil, errors.New("no watsonx API key provided")
 	}
 
-	if opts.projectID == "" {
-		return nil, errors.New("no watsonx project ID provided")
+	if opts.projectID == "" && opts.spaceID == "" {
+		// Fall back to the environment only when neither a project nor a space was given
+		opts.projectID = os.Getenv(WatsonxProjectIDEnvVarName)
+	}
+
+	if opts.projectID != "" && opts.spaceID != "" {
+		return nil, errors.New("watsonx project ID and space ID are mutually exclusive")
+	}
+
+	if opts.projectID == "" && opts.spaceID == "" {
+		return nil, errors.New("no watsonx project ID or space ID provided")
 	}
 
 	m := &Client{
 		url:        opts.URL,
+		baseURL:    baseURL,
 		iam:        opts.IAM,
 		region:     opts.Region,
 		apiVersion: opts.APIVersion,
@@ -60,8 +125,20 @@ func NewClient(options ...ClientOption) (*Client, error) {
 		// token: set below
 		apiKey:    opts.apiKey,
 		projectID: opts.projectID,
+		spaceID:   opts.spaceID,
+
+		httpClient:     opts.HttpClient,
+		defaultHeaders: opts.Headers,
+		userAgent:      opts.UserAgent,
 
-		httpClient: NewHttpClient(),
+		maxStreamEventSize: opts.MaxStreamEventSize,
+		clock:              opts.Clock,
+		codec:              opts.Codec,
+		warningLogger:      opts.WarningLogger,
+		modelSpecs:         newModelSpecCache(opts.ModelSpecCacheTTL),
+		defaultModel:       opts.DefaultModel,
+		tokenizer:          opts.Tokenizer,
+		modelTokenizers:    opts.ModelTokenizers,
 	}
 
 	err := m.RefreshToken()
@@ -72,16 +149,106 @@ func NewClient(options ...ClientOption) (*Client, error) {
 	return m, nil
 }
 
-// CheckAndRefreshToken checks the IAM token if it expired; if it did, it refreshes it; nothing if not
-func (m *Client) CheckAndRefreshToken() error {
-	if m.token.Expired() {
-		return m.RefreshToken()
+// NewClientFromEnv creates a client configured from the environment: WATSONX_API_KEY, WATSONX_PROJECT_ID
+// or WATSONX_SPACE_ID, and optionally WATSONX_URL or WATSONX_REGION, WATSONX_API_VERSION and WATSONX_IAM_HOST.
+// It fails with an error naming every missing variable. The options are applied after the environment and override it.
+func NewClientFromEnv(options ...ClientOption) (*Client, error) {
+	apiKey := os.Getenv(WatsonxAPIKeyEnvVarName)
+	projectID := os.Getenv(WatsonxProjectIDEnvVarName)
+	spaceID := os.Getenv(WatsonxSpaceIDEnvVarName)
+
+	var missing []string
+	if apiKey == "" {
+		missing = append(missing, WatsonxAPIKeyEnvVarName)
+	}
+	if projectID == "" && spaceID == "" {
+		missing = append(missing, WatsonxProjectIDEnvVarName+" or "+WatsonxSpaceIDEnvVarName)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing environment variables: %s", strings.Join(missing, ", "))
+	}
+
+	envOptions := []ClientOption{WithWatsonxAPIKey(apiKey)}
+	if projectID != "" {
+		envOptions = append(envOptions, WithWatsonxProjectID(projectID))
+	} else {
+		envOptions = append(envOptions, WithWatsonxSpaceID(spaceID))
+	}
+	if baseURL := os.Getenv(WatsonxBaseURLEnvVarName); baseURL != "" {
+		envOptions = append(envOptions, WithBaseURL(baseURL))
+	}
+	if region := os.Getenv(WatsonxRegionEnvVarName); region != "" {
+		envOptions = append(envOptions, WithRegion(region))
+	}
+	if apiVersion := os.Getenv(WatsonxAPIVersionEnvVarName); apiVersion != "" {
+		envOptions = append(envOptions, WithAPIVersion(apiVersion))
 	}
+
+	return NewClient(append(envOptions, options...)...)
+}
+
+// Close releases the resources held by the client, such as pooled idle connections.
+// The client can still be used afterwards, at the cost of opening new connections.
+func (m *Client) Close() error {
+	if closer, ok := m.httpClient.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Ping checks the credentials and the connection to watsonx, e.g. for a readiness probe, with a cheap
+// authenticated request listing a single deployment of the client's project or space, so a missing
+// permission on the project or space fails too. It returns nil when watsonx answers, and otherwise
+// an error that ClassifyError classifies, such as ErrorClassAuth for rejected credentials.
+func (m *Client) Ping(ctx context.Context) error {
+	if err := m.CheckAndRefreshToken(); err != nil {
+		return err
+	}
+
+	req, err := m.newGetRequest(ctx, DeploymentEndpoint, url.Values{"limit": {"1"}})
+	if err != nil {
+		return err
+	}
+
+	res, err := m.httpClient.DoWithRetry(req)
+	if err != nil {
+		return err
+	}
+	drainAndClose(res.Body)
+
 	return nil
 }
 
+// CheckAndRefreshToken checks the IAM token if it expired; if it did, it refreshes it; nothing if not.
+// It is safe for concurrent use: when several requests find the token expired, it is refreshed once.
+func (m *Client) CheckAndRefreshToken() error {
+	m.tokenMu.RLock()
+	expired := m.token.expiredAt(m.clock.Now())
+	m.tokenMu.RUnlock()
+	if !expired {
+		return nil
+	}
+
+	m.tokenMu.Lock()
+	defer m.tokenMu.Unlock()
+
+	// Another request may have refreshed the token while waiting for the lock
+	if !m.token.expiredAt(m.clock.Now()) {
+		return nil
+	}
+	return m.refreshToken()
+}
+
 // RefreshToken generates and sets the model with a new token
 func (m *Client) RefreshToken() error {
+	m.tokenMu.Lock()
+	defer m.tokenMu.Unlock()
+
+	return m.refreshToken()
+}
+
+// refreshToken generates a new token; the caller must hold tokenMu
+func (m *Client) refreshToken() error {
 	token, err := GenerateToken(m.httpClient, m.apiKey, m.iam)
 	if err != nil {
 		return err
@@ -90,11 +257,53 @@ func (m *Client) RefreshToken() error {
 	return nil
 }
 
+// accessToken returns the current IAM access token
+func (m *Client) accessToken() string {
+	m.tokenMu.RLock()
+	defer m.tokenMu.RUnlock()
+
+	return m.token.value
+}
+
+// logWarnings logs the warnings of a response with the warning logger, if one is set
+func (m *Client) logWarnings(system *SystemDetails) {
+	if m.warningLogger == nil || system == nil {
+		return
+	}
+	for _, warning := range system.Warnings {
+		if warning.ID != "" {
+			m.warningLogger.Printf("watsonx warning %s: %s", warning.ID, warning.Message)
+		} else {
+			m.warningLogger.Printf("watsonx warning: %s", warning.Message)
+		}
+	}
+}
+
+// modelOrDefault returns the model, or the default model of the client when it is empty
+func (m *Client) modelOrDefault(model string) string {
+	if model == "" {
+		return m.defaultModel
+	}
+	return model
+}
+
+// errNoModel is wrapped in the RequestError of a call that names no model while the client has no default model
+var errNoModel = errors.New("no model ID given and no default model set")
+
 // generateUrlFromEndpoint generates a URL from the endpoint and the client's configuration
 func (m *Client) generateUrlFromEndpoint(endpoint string) string {
+	return m.generateUrlWithQuery(endpoint, nil)
+}
+
+// generateUrlWithQuery generates a URL from the endpoint and the client's configuration,
+// adding the query parameters to the version
+func (m *Client) generateUrlWithQuery(endpoint string, query url.Values) string {
 	params := url.Values{
 		"version": {m.apiVersion},
 	}
+	for name, values := range query {
+		params[name] = values
+	}
 
 	generateTextURL := url.URL{
 		Scheme:   "https",
@@ -103,9 +312,115 @@ func (m *Client) generateUrlFromEndpoint(endpoint string) string {
 		RawQuery: params.Encode(),
 	}
 
+	if m.baseURL != nil {
+		generateTextURL.Scheme = m.baseURL.Scheme
+		generateTextURL.Host = m.baseURL.Host
+		generateTextURL.Path = strings.TrimSuffix(m.baseURL.Path, "/") + endpoint
+	}
+
 	return generateTextURL.String()
 }
 
+// newJSONRequest creates a POST request to the endpoint with the JSON-encoded payload and the authorization headers
+func (m *Client) newJSONRequest(ctx context.Context, endpoint string, payload interface{}) (*http.Request, error) {
+	payloadJSON, err := m.codec.Marshal(payload)
+	if err != nil {
+		return nil, &RequestError{Op: "encode payload", Err: err}
+	}
+
+	return m.newRequest(ctx, http.MethodPost, m.generateUrlFromEndpoint(endpoint), "application/json", bytes.NewReader(payloadJSON))
+}
+
+// newGetRequest creates a GET request to the endpoint, scoped to the client's project or space
+func (m *Client) newGetRequest(ctx context.Context, endpoint string, query url.Values) (*http.Request, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	if m.spaceID != "" {
+		query.Set("space_id", m.spaceID)
+	} else {
+		query.Set("project_id", m.projectID)
+	}
+
+	return m.newRequest(ctx, http.MethodGet, m.generateUrlWithQuery(endpoint, query), "", nil)
+}
+
+// newRequest creates a request with the user agent, default, content type, context and authorization headers
+func (m *Client) newRequest(ctx context.Context, method, rawURL, contentType string, body io.Reader) (*http.Request, error) {
+	if ctx == nil {
+		return nil, &RequestError{Op: "create request", Err: errors.New("nil context")}
+	}
+
+	req, err := http.NewRequestWithContext(contextWithCodec(ctx, m.codec), method, rawURL, body)
+	if err != nil {
+		return nil, &RequestError{Op: "create request", Err: err}
+	}
+
+	req.Header.Set("User-Agent", m.userAgent)
+
+	for name, values := range m.defaultHeaders {
+		req.Header[name] = append([]string(nil), values...)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	for name, values := range headersFromContext(ctx) {
+		req.Header[name] = append([]string(nil), values...)
+	}
+
+	// Set last, so neither default nor per-call headers can replace the bearer token
+	req.Header.Set("Authorization", "Bearer "+m.accessToken())
+
+	return req, nil
+}
+
+// parseBaseURL validates a base URL override, which must be an absolute http(s) URL with a host
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	baseURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", rawURL, err)
+	}
+
+	if baseURL.Scheme != "https" && baseURL.Scheme != "http" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", rawURL)
+	}
+
+	if baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing host", rawURL)
+	}
+
+	return baseURL, nil
+}
+
+// validateAPIVersion checks that the API version is a YYYY-MM-DD date
+func validateAPIVersion(apiVersion string) error {
+	if _, err := time.Parse(APIVersionLayout, apiVersion); err != nil {
+		return fmt.Errorf("invalid API version %q: expected a YYYY-MM-DD date", apiVersion)
+	}
+	return nil
+}
+
+// knownRegions lists the regions where watsonx.ai is available
+var knownRegions = map[IBMCloudRegion]bool{
+	RegionDallas:    true,
+	RegionFrankfurt: true,
+	RegionTokyo:     true,
+	RegionLondon:    true,
+	RegionSydney:    true,
+	RegionToronto:   true,
+}
+
+// RegionHost returns the watsonx host of a known region, e.g. "us-south.ml.cloud.ibm.com" for RegionDallas.
+// Use WithURL to reach a region that is not listed yet.
+func RegionHost(region IBMCloudRegion) (string, error) {
+	if !knownRegions[region] {
+		return "", fmt.Errorf("unknown watsonx region %q", region)
+	}
+	return buildBaseURL(region), nil
+}
+
 func buildBaseURL(region IBMCloudRegion) string {
 	return fmt.Sprintf(BaseURLFormatStr, region)
 }
@@ -116,8 +431,11 @@ func defaulClientOptions() *ClientOptions {
 		IAM:        os.Getenv(WatsonxIAMEnvVarName),
 		Region:     DefaultRegion,
 		APIVersion: DefaultAPIVersion,
+		UserAgent:  DefaultUserAgent,
+
+		ModelSpecCacheTTL: DefaultModelSpecCacheTTL,
 
-		apiKey:    os.Getenv(WatsonxAPIKeyEnvVarName),
-		projectID: os.Getenv(WatsonxProjectIDEnvVarName),
+		apiKey: os.Getenv(WatsonxAPIKeyEnvVarName),
+		// projectID: read from the environment in NewClient unless a project or space is given
 	}
 }
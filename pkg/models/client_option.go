@@ -1,15 +1,35 @@
 package models
 
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
 type ClientOption func(*ClientOptions)
 
 type ClientOptions struct {
 	URL        string
+	BaseURL    string
 	IAM        string
 	Region     IBMCloudRegion
 	APIVersion string
+	HttpClient Doer
+	Headers    http.Header
+	UserAgent  string
+
+	MaxStreamEventSize int
+	Clock              Clock
+	Codec              Codec
+	WarningLogger      *log.Logger
+	ModelSpecCacheTTL  time.Duration
+	DefaultModel       string
+	Tokenizer          Tokenizer
+	ModelTokenizers    map[string]Tokenizer
 
 	apiKey    WatsonxAPIKey
 	projectID WatsonxProjectID
+	spaceID   WatsonxSpaceID
 }
 
 func WithURL(url string) ClientOption {
@@ -18,24 +38,72 @@ func WithURL(url string) ClientOption {
 	}
 }
 
+// WithBaseURL fully overrides the watsonx URL, e.g. to route requests through an internal gateway.
+// Unlike WithURL it accepts a scheme and a path prefix, such as "https://gateway.internal:8443/watsonx".
+// It takes precedence over WithURL and WithRegion.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(o *ClientOptions) {
+		o.BaseURL = baseURL
+	}
+}
+
 func WithIAM(iam string) ClientOption {
 	return func(o *ClientOptions) {
 		o.IAM = iam
 	}
 }
 
+// WithRegion selects the watsonx region, such as RegionFrankfurt. NewClient fails for an unknown region,
+// unless the URL is set with WithURL or WithBaseURL, e.g. to reach a region that is not listed yet.
 func WithRegion(region IBMCloudRegion) ClientOption {
 	return func(o *ClientOptions) {
 		o.Region = region
 	}
 }
 
+// WithAPIVersion sets the YYYY-MM-DD version date sent with every request, defaulting to DefaultAPIVersion.
+// NewClient fails if the date is malformed.
 func WithAPIVersion(apiVersion string) ClientOption {
 	return func(o *ClientOptions) {
 		o.APIVersion = apiVersion
 	}
 }
 
+// WithHttpClient sets the Doer used to send requests, such as an HttpClient created with custom HttpClientOptions
+func WithHttpClient(httpClient Doer) ClientOption {
+	return func(o *ClientOptions) {
+		o.HttpClient = httpClient
+	}
+}
+
+// WithDefaultHeaders sets headers sent with every watsonx request, such as routing tags or experiment headers.
+// Headers set on the context with ContextWithHeaders take precedence over the defaults.
+func WithDefaultHeaders(headers http.Header) ClientOption {
+	return func(o *ClientOptions) {
+		if o.Headers == nil {
+			o.Headers = http.Header{}
+		}
+		for name, values := range headers {
+			o.Headers[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// WithUserAgent overrides the default "watsonx-go/<version>" User-Agent of watsonx requests
+func WithUserAgent(userAgent string) ClientOption {
+	return func(o *ClientOptions) {
+		o.UserAgent = userAgent
+	}
+}
+
+// WithMaxStreamEventSize sets the maximum size of a line of a streamed event, defaulting to DefaultMaxStreamEventSize.
+// Streams with longer lines fail with bufio.ErrTooLong.
+func WithMaxStreamEventSize(size int) ClientOption {
+	return func(o *ClientOptions) {
+		o.MaxStreamEventSize = size
+	}
+}
+
 func WithWatsonxAPIKey(watsonxAPIKey WatsonxAPIKey) ClientOption {
 	return func(o *ClientOptions) {
 		o.apiKey = watsonxAPIKey
@@ -47,3 +115,71 @@ func WithWatsonxProjectID(projectID WatsonxProjectID) ClientOption {
 		o.projectID = projectID
 	}
 }
+
+// WithWatsonxSpaceID runs requests in a deployment space instead of a project.
+// It is mutually exclusive with WithWatsonxProjectID; NewClient fails if both are set.
+func WithWatsonxSpaceID(spaceID WatsonxSpaceID) ClientOption {
+	return func(o *ClientOptions) {
+		o.spaceID = spaceID
+	}
+}
+
+// WithWarningLogger logs the warnings sent by watsonx with successful responses, which are otherwise only
+// exposed on the response. A nil logger uses the standard logger.
+func WithWarningLogger(logger *log.Logger) ClientOption {
+	return func(o *ClientOptions) {
+		if logger == nil {
+			logger = log.Default()
+		}
+		o.WarningLogger = logger
+	}
+}
+
+// WithModelSpecCacheTTL sets how long the specs fetched by GetModelSpec are cached, defaulting to
+// DefaultModelSpecCacheTTL. A TTL of zero or less disables the cache.
+func WithModelSpecCacheTTL(ttl time.Duration) ClientOption {
+	return func(o *ClientOptions) {
+		o.ModelSpecCacheTTL = ttl
+	}
+}
+
+// WithCodec sets the Codec used to encode request bodies and decode response and error bodies, defaulting to encoding/json
+func WithCodec(codec Codec) ClientOption {
+	return func(o *ClientOptions) {
+		o.Codec = codec
+	}
+}
+
+// WithClientClock sets the Clock used to check the expiration of the IAM token, defaulting to the system time
+func WithClientClock(clock Clock) ClientOption {
+	return func(o *ClientOptions) {
+		o.Clock = clock
+	}
+}
+
+// WithDefaultModel sets the model used by generation, chat and embedding calls that do not name one.
+// A model given to a call overrides the default.
+func WithDefaultModel(modelID string) ClientOption {
+	return func(o *ClientOptions) {
+		o.DefaultModel = modelID
+	}
+}
+
+// WithTokenizer sets the Tokenizer used by CountInputTokens for every model without its own WithModelTokenizer,
+// which otherwise counts tokens with a tokenization request. It should only be used when the client uses
+// a single model, or models sharing the same tokenizer, since the counts of another tokenizer are wrong.
+func WithTokenizer(tokenizer Tokenizer) ClientOption {
+	return func(o *ClientOptions) {
+		o.Tokenizer = tokenizer
+	}
+}
+
+// WithModelTokenizer sets the Tokenizer used by CountInputTokens for the model, taking precedence over WithTokenizer
+func WithModelTokenizer(modelID string, tokenizer Tokenizer) ClientOption {
+	return func(o *ClientOptions) {
+		if o.ModelTokenizers == nil {
+			o.ModelTokenizers = map[string]Tokenizer{}
+		}
+		o.ModelTokenizers[modelID] = tokenizer
+	}
+}
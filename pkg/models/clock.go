@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Clock abstracts the current time so that time-based behavior, such as the max elapsed time
+// of retries or the expiration of IAM tokens, can be tested without sleeping.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock implements Clock using time.Now
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// expiredAt reports whether the token is expired at the given time
+func (t *IAMToken) expiredAt(now time.Time) bool {
+	return t.expiration.Before(now)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Codec encodes request bodies and decodes response and error bodies,
+// so that a faster JSON implementation can replace encoding/json.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StreamDecoder is implemented by a Codec that can decode straight from a reader. Responses whose raw bytes
+// are not kept are then decoded as they are read instead of being buffered whole first.
+type StreamDecoder interface {
+	Decode(r io.Reader, v interface{}) error
+}
+
+// jsonCodec implements Codec and StreamDecoder with encoding/json
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Decode(r io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	if streamer, ok := v.(jsonStreamer); ok {
+		return streamer.decodeStream(decoder)
+	}
+	return decoder.Decode(v)
+}
+
+// jsonStreamer is implemented by responses with large arrays, decoded one element at a time so that
+// the decoder never buffers more than a single element, rather than the whole response
+type jsonStreamer interface {
+	decodeStream(decoder *json.Decoder) error
+}
+
+// decodeObjectStream decodes a JSON object into v, except the array of arrayKey whose elements are passed
+// to decodeElement one at a time. The other fields are small and decoded with encoding/json once collected.
+func decodeObjectStream(decoder *json.Decoder, v interface{}, arrayKey string, decodeElement func(*json.Decoder) error) error {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	fields := map[string]json.RawMessage{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+
+		if key != arrayKey {
+			var field json.RawMessage
+			if err := decoder.Decode(&field); err != nil {
+				return err
+			}
+			fields[key] = field
+			continue
+		}
+
+		token, err = decoder.Token()
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			continue
+		}
+		if token != json.Delim('[') {
+			return fmt.Errorf("invalid JSON: expected [ for %q, got %v", key, token)
+		}
+		for decoder.More() {
+			if err := decodeElement(decoder); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return err
+		}
+	}
+
+	if err := expectDelim(decoder, '}'); err != nil {
+		return err
+	}
+
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(rest, v)
+}
+
+// expectDelim reads the next token, which must be the delimiter
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("invalid JSON: expected %v, got %v", delim, token)
+	}
+	return nil
+}
+
+type codecContextKey struct{}
+
+// contextWithCodec carries the codec of the client to the retry loop, which decodes error responses
+func contextWithCodec(ctx context.Context, codec Codec) context.Context {
+	return context.WithValue(ctx, codecContextKey{}, codec)
+}
+
+// codecFromContext returns the codec set with contextWithCodec, or the encoding/json codec
+func codecFromContext(ctx context.Context) Codec {
+	if codec, ok := ctx.Value(codecContextKey{}).(Codec); ok {
+		return codec
+	}
+	return jsonCodec{}
+}
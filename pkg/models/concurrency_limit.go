@@ -0,0 +1,25 @@
+package models
+
+import "context"
+
+// concurrencyLimiter caps the number of requests in flight with a counting semaphore
+type concurrencyLimiter chan struct{}
+
+func newConcurrencyLimiter(n int) concurrencyLimiter {
+	return make(concurrencyLimiter, n)
+}
+
+// acquire blocks until a slot is free or the context is done
+func (l concurrencyLimiter) acquire(ctx context.Context) error {
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot taken by acquire
+func (l concurrencyLimiter) release() {
+	<-l
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// COSDataReferenceType is the data reference type of a file reached through a connection asset
+const COSDataReferenceType = "connection_asset"
+
+// COSReference points to a file in Cloud Object Storage through a connection asset.
+// It is encoded as a watsonx data_reference and is shared by text extractions and batch jobs.
+type COSReference struct {
+	ConnectionID string // ID of the connection asset holding the COS credentials
+	Bucket       string
+	FileName     string // Key of the object in the bucket
+}
+
+// cosDataReference is the data_reference schema of watsonx
+type cosDataReference struct {
+	Type       string `json:"type"`
+	Connection struct {
+		ID string `json:"id"`
+	} `json:"connection"`
+	Location struct {
+		Bucket   string `json:"bucket"`
+		FileName string `json:"file_name"`
+	} `json:"location"`
+}
+
+// Validate checks that the connection, the bucket and the file name are set
+func (r COSReference) Validate() error {
+	if r.ConnectionID == "" {
+		return errors.New("COS connection ID cannot be empty")
+	}
+	if r.Bucket == "" {
+		return errors.New("COS bucket cannot be empty")
+	}
+	if r.FileName == "" {
+		return errors.New("COS file name cannot be empty")
+	}
+	return nil
+}
+
+func (r COSReference) MarshalJSON() ([]byte, error) {
+	var ref cosDataReference
+	ref.Type = COSDataReferenceType
+	ref.Connection.ID = r.ConnectionID
+	ref.Location.Bucket = r.Bucket
+	ref.Location.FileName = r.FileName
+	return json.Marshal(ref)
+}
+
+func (r *COSReference) UnmarshalJSON(data []byte) error {
+	var ref cosDataReference
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return err
+	}
+	*r = COSReference{
+		ConnectionID: ref.Connection.ID,
+		Bucket:       ref.Location.Bucket,
+		FileName:     ref.Location.FileName,
+	}
+	return nil
+}
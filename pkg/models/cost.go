@@ -0,0 +1,58 @@
+package models
+
+import "sync"
+
+// ModelPrice is the price of a model per 1000 tokens, in the currency of the caller's choosing
+type ModelPrice struct {
+	InputPer1K  float64
+	OutputPer1K float64
+}
+
+// CostEstimator estimates the cost of generations from their token usage and a user-supplied price table.
+// It also keeps a running total of the recorded generations; it is safe for concurrent use.
+type CostEstimator struct {
+	prices map[string]ModelPrice
+
+	mu    sync.Mutex
+	total float64
+}
+
+// NewCostEstimator creates an estimator with prices keyed by model ID
+func NewCostEstimator(prices map[string]ModelPrice) *CostEstimator {
+	e := &CostEstimator{
+		prices: make(map[string]ModelPrice, len(prices)),
+	}
+	for modelID, price := range prices {
+		e.prices[modelID] = price
+	}
+	return e
+}
+
+// Estimate returns the cost of the usage with the prices of the model, or 0 if the model has no price
+func (e *CostEstimator) Estimate(modelID string, usage GenerationUsage) float64 {
+	price, ok := e.prices[modelID]
+	if !ok {
+		return 0
+	}
+	return float64(usage.InputTokenCount)/1000*price.InputPer1K +
+		float64(usage.GeneratedTokenCount)/1000*price.OutputPer1K
+}
+
+// Record adds the estimated cost of the usage to the running total and returns it
+func (e *CostEstimator) Record(modelID string, usage GenerationUsage) float64 {
+	cost := e.Estimate(modelID, usage)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.total += cost
+	return cost
+}
+
+// Total returns the cumulative cost of the recorded usages
+func (e *CostEstimator) Total() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.total
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type callDeadlineContextKey struct{}
+
+// ContextWithDeadline returns a context that bounds a whole call, including its retries, by a single deadline.
+// The deadline cancels in-flight requests, is sent as the generation time_limit when none is set,
+// and stops retrying as soon as the next attempt could not start before it, see WithMaxElapsedTime.
+func ContextWithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	return context.WithValue(ctx, callDeadlineContextKey{}, deadline), cancel
+}
+
+// callDeadlineFromContext returns the deadline set with ContextWithDeadline
+func callDeadlineFromContext(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(callDeadlineContextKey{}).(time.Time)
+	return deadline, ok
+}
@@ -0,0 +1,260 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	DeploymentEndpoint string = "/ml/v1/deployments"
+	// Need to call Sprintf on it with the deployment ID
+	DeploymentGenerateTextEndpointFormat string = DeploymentEndpoint + "/%s/text/generation"
+)
+
+// DeploymentGenerateTextPayload is the generation payload for a deployed model.
+// The deployment is identified by the URL path, so there is no model or project ID.
+type DeploymentGenerateTextPayload struct {
+	Prompt      string           `json:"input"`
+	Parameters  *GenerateOptions `json:"parameters,omitempty"`
+	Moderations *Moderations     `json:"moderations,omitempty"`
+}
+
+// PromptTemplateGenerateTextPayload is the generation payload for a deployed prompt template asset.
+// The prompt is stored in the template, so only its variables are sent.
+type PromptTemplateGenerateTextPayload struct {
+	Parameters  PromptTemplateParameters `json:"parameters"`
+	Moderations *Moderations             `json:"moderations,omitempty"`
+}
+
+// PromptTemplateParameters are the generation parameters along with the values of the template variables
+type PromptTemplateParameters struct {
+	*GenerateOptions
+	PromptVariables map[string]string `json:"prompt_variables"`
+}
+
+// deploymentIDPattern matches the IDs and serving names that can be used in a deployment path
+var deploymentIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// validateDeploymentID checks that the ID can be used as a single segment of the deployment path
+func validateDeploymentID(deploymentID string) error {
+	if deploymentID == "" {
+		return errors.New("deployment ID cannot be empty")
+	}
+	if !deploymentIDPattern.MatchString(deploymentID) {
+		return fmt.Errorf("invalid deployment ID %q: only letters, digits, '-' and '_' are allowed", deploymentID)
+	}
+	return nil
+}
+
+// GenerateFromDeployment generates completion text using a deployed model rather than a base model.
+// The Model of the request is ignored. Like Generate, it derives the time limit from the deadline of ctx.
+func (m *Client) GenerateFromDeployment(ctx context.Context, deploymentID string, req GenerateTextRequest) (GenerateTextResult, error) {
+	m.CheckAndRefreshToken()
+
+	if req.Prompt == "" {
+		return GenerateTextResult{}, errors.New("prompt cannot be empty")
+	}
+
+	opts := newGenerateOptions(ctx, req.Options...)
+
+	response, err := m.generateFromDeployment(ctx, deploymentID, req.Prompt, opts)
+	if err != nil {
+		return GenerateTextResult{}, err
+	}
+
+	return response.Results[0], nil
+}
+
+// generateFromDeployment sends a generation request to the deployment endpoint
+func (m *Client) generateFromDeployment(ctx context.Context, deploymentID, prompt string, opts *GenerateOptions) (generateTextResponse, error) {
+	if err := validateDeploymentID(deploymentID); err != nil {
+		return generateTextResponse{}, err
+	}
+
+	return m.sendDeploymentGeneration(ctx, deploymentID, newDeploymentGenerateTextPayload(prompt, opts))
+}
+
+// newDeploymentGenerateTextPayload builds the generation payload of a deployment, which is bound to its model
+func newDeploymentGenerateTextPayload(prompt string, opts *GenerateOptions) DeploymentGenerateTextPayload {
+	return DeploymentGenerateTextPayload{
+		Prompt:      prompt,
+		Parameters:  opts,
+		Moderations: opts.Moderations,
+	}
+}
+
+// sendDeploymentGeneration sends a generation payload to the deployment endpoint
+func (m *Client) sendDeploymentGeneration(ctx context.Context, deploymentID string, payload interface{}) (generateTextResponse, error) {
+	endpoint := fmt.Sprintf(DeploymentGenerateTextEndpointFormat, deploymentID)
+
+	httpReq, err := m.newJSONRequest(ctx, endpoint, payload)
+	if err != nil {
+		return generateTextResponse{}, err
+	}
+
+	res, err := m.httpClient.DoWithRetry(httpReq)
+	if err != nil {
+		return generateTextResponse{}, err
+	}
+	defer drainAndClose(res.Body)
+
+	var generateRes generateTextResponse
+	raw, err := decodeJSONBody(m.codec, res, &generateRes)
+	if err != nil {
+		return generateTextResponse{}, err
+	}
+	generateRes.Raw = raw
+	generateRes.RateLimit = ParseRateLimitInfo(res.Header)
+	m.logWarnings(generateRes.System)
+
+	if len(generateRes.Results) == 0 {
+		return generateTextResponse{}, errors.New("no result received")
+	}
+
+	return generateRes, nil
+}
+
+// Deployment describes a model deployment of the project or space
+type Deployment struct {
+	ID          string
+	Name        string
+	CreatedAt   time.Time
+	AssetID     string // ID of the backing model asset, such as a tuned model; empty for base model deployments
+	BaseModelID string // ID of the backing base model, if any
+	State       string // e.g. "initializing", "ready" or "failed"
+}
+
+// ListDeploymentsOptions filters and pages the deployments listed by ListDeployments
+type ListDeploymentsOptions struct {
+	Limit int // Number of deployments fetched per page; the server default is used when zero
+}
+
+// deploymentResource is a deployment in the deployments page
+type deploymentResource struct {
+	Metadata struct {
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"metadata"`
+	Entity struct {
+		Asset *struct {
+			ID string `json:"id"`
+		} `json:"asset,omitempty"`
+		BaseModelID string `json:"base_model_id"`
+		Status      struct {
+			State string `json:"state"`
+		} `json:"status"`
+	} `json:"entity"`
+}
+
+func (r deploymentResource) deployment() Deployment {
+	deployment := Deployment{
+		ID:          r.Metadata.ID,
+		Name:        r.Metadata.Name,
+		CreatedAt:   r.Metadata.CreatedAt,
+		BaseModelID: r.Entity.BaseModelID,
+		State:       r.Entity.Status.State,
+	}
+	if r.Entity.Asset != nil {
+		deployment.AssetID = r.Entity.Asset.ID
+	}
+	return deployment
+}
+
+// deploymentsPage is a page of the deployments endpoint
+type deploymentsPage struct {
+	Resources []deploymentResource `json:"resources"`
+	Next      *struct {
+		Href string `json:"href"`
+	} `json:"next,omitempty"`
+}
+
+// ListDeployments returns every deployment of the client's project or space, walking all the pages
+func (m *Client) ListDeployments(ctx context.Context, opts ListDeploymentsOptions) ([]Deployment, error) {
+	return NewPaginator(func(ctx context.Context, cursor string) ([]Deployment, string, error) {
+		return m.listDeploymentsPage(ctx, opts, cursor)
+	}).All(ctx)
+}
+
+// listDeploymentsPage fetches the deployments page starting at cursor and returns the cursor of the next page
+func (m *Client) listDeploymentsPage(ctx context.Context, opts ListDeploymentsOptions, cursor string) ([]Deployment, string, error) {
+	m.CheckAndRefreshToken()
+
+	query := url.Values{}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if cursor != "" {
+		query.Set("start", cursor)
+	}
+
+	httpReq, err := m.newGetRequest(ctx, DeploymentEndpoint, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := m.httpClient.DoWithRetry(httpReq)
+	if err != nil {
+		return nil, "", err
+	}
+	defer drainAndClose(res.Body)
+
+	var page deploymentsPage
+	if err := decodeJSONStream(m.codec, res, &page); err != nil {
+		return nil, "", err
+	}
+
+	deployments := make([]Deployment, 0, len(page.Resources))
+	for _, resource := range page.Resources {
+		deployments = append(deployments, resource.deployment())
+	}
+
+	// The next page is linked with its start token
+	var next string
+	if page.Next != nil && page.Next.Href != "" {
+		nextURL, err := url.Parse(page.Next.Href)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid next page link %q: %w", page.Next.Href, err)
+		}
+		next = nextURL.Query().Get("start")
+	}
+
+	return deployments, next, nil
+}
+
+// GenerateFromPromptTemplate generates text from a prompt template asset deployed with the given ID.
+// The template is rendered on the server with the variables, and the options override its stored parameters.
+// Like Generate, it derives the time limit from the deadline of ctx.
+func (m *Client) GenerateFromPromptTemplate(ctx context.Context, templateID string, vars map[string]string, options ...GenerateOption) (GenerateTextResult, error) {
+	m.CheckAndRefreshToken()
+
+	if err := validateDeploymentID(templateID); err != nil {
+		return GenerateTextResult{}, err
+	}
+
+	opts := newGenerateOptions(ctx, options...)
+
+	if vars == nil {
+		vars = map[string]string{}
+	}
+
+	payload := PromptTemplateGenerateTextPayload{
+		Parameters: PromptTemplateParameters{
+			GenerateOptions: opts,
+			PromptVariables: vars,
+		},
+		Moderations: opts.Moderations,
+	}
+
+	response, err := m.sendDeploymentGeneration(ctx, templateID, payload)
+	if err != nil {
+		return GenerateTextResult{}, err
+	}
+
+	return response.Results[0], nil
+}
@@ -1,7 +1,7 @@
 package models
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -13,7 +13,8 @@ const (
 )
 
 type EmbeddingPayload struct {
-	ProjectID  string            `json:"project_id"`
+	ProjectID  string            `json:"project_id,omitempty"`
+	SpaceID    string            `json:"space_id,omitempty"`
 	Model      string            `json:"model_id"`
 	Inputs     []string          `json:"inputs"`
 	Parameters *EmbeddingOptions `json:"parameters,omitempty"`
@@ -23,7 +24,7 @@ type EmbeddingResponse struct {
 	Model           string            `json:"model_id"`
 	Results         []EmbeddingResult `json:"results"`
 	CreatedAt       time.Time         `json:"created_at"`
-	InputTokenCount int               `json:"input_token_count"`
+	InputTokenCount int64             `json:"input_token_count"`
 }
 
 type EmbeddingResult struct {
@@ -37,25 +38,28 @@ type embeddingResponse struct {
 	EmbeddingResponse
 }
 
+// decodeStream decodes the embeddings one at a time, since a batch of vectors can weigh megabytes
+func (r *embeddingResponse) decodeStream(decoder *json.Decoder) error {
+	return decodeObjectStream(decoder, r, "results", func(decoder *json.Decoder) error {
+		var result EmbeddingResult
+		if err := decoder.Decode(&result); err != nil {
+			return err
+		}
+		r.Results = append(r.Results, result)
+		return nil
+	})
+}
+
 // EmbedDocuments embeds the given texts using the specified model.
 func (m *Client) EmbedDocuments(model string, texts []string, options ...EmbeddingOption) (EmbeddingResponse, error) {
 	m.CheckAndRefreshToken()
 
-	opts := &EmbeddingOptions{}
-	for _, opt := range options {
-		if opt != nil {
-			opt(opts)
-		}
-	}
-
-	payload := EmbeddingPayload{
-		ProjectID:  m.projectID,
-		Model:      model,
-		Inputs:     texts,
-		Parameters: opts,
+	payload := m.newEmbeddingPayload(model, texts, options...)
+	if payload.Model == "" {
+		return EmbeddingResponse{}, &RequestError{Op: "select model", Err: errNoModel}
 	}
 
-	response, err := m.generateEmbeddingRequest(payload)
+	response, err := m.generateEmbeddingRequest(context.Background(), payload)
 	if err != nil {
 		return EmbeddingResponse{}, err
 	}
@@ -72,33 +76,57 @@ func (m *Client) EmbedQuery(model string, text string, options ...EmbeddingOptio
 	return m.EmbedDocuments(model, []string{text}, options...)
 }
 
-// generateEmbeddingRequest sends a request to the embedding endpoint with the given payload.
-// return the response from the server if and only if the request is successful, code 200.
-func (m *Client) generateEmbeddingRequest(payload EmbeddingPayload) (embeddingResponse, error) {
-	embeddingUrl := m.generateUrlFromEndpoint(EmbeddingEndpoint)
+// BuildEmbeddingRequest builds the fully-formed embedding request (URL, headers and body) without sending it,
+// so it can be inspected or snapshotted
+func (m *Client) BuildEmbeddingRequest(model string, texts []string, options ...EmbeddingOption) (*http.Request, error) {
+	if err := m.CheckAndRefreshToken(); err != nil {
+		return nil, err
+	}
 
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		return embeddingResponse{}, err
+	payload := m.newEmbeddingPayload(model, texts, options...)
+	if payload.Model == "" {
+		return nil, &RequestError{Op: "select model", Err: errNoModel}
 	}
 
-	req, err := http.NewRequest(http.MethodPost, embeddingUrl, bytes.NewBuffer(payloadJSON))
+	return m.newJSONRequest(context.Background(), EmbeddingEndpoint, payload)
+}
+
+// newEmbeddingPayload builds the embedding payload from the model, texts and options,
+// falling back to the default model of the client
+func (m *Client) newEmbeddingPayload(model string, texts []string, options ...EmbeddingOption) EmbeddingPayload {
+	opts := &EmbeddingOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	return EmbeddingPayload{
+		ProjectID:  m.projectID,
+		SpaceID:    m.spaceID,
+		Model:      m.modelOrDefault(model),
+		Inputs:     texts,
+		Parameters: opts,
+	}
+}
+
+// generateEmbeddingRequest sends a request to the embedding endpoint with the given payload.
+// return the response from the server if and only if the request is successful, code 200.
+func (m *Client) generateEmbeddingRequest(ctx context.Context, payload EmbeddingPayload) (embeddingResponse, error) {
+	req, err := m.newJSONRequest(ctx, EmbeddingEndpoint, payload)
 	if err != nil {
 		return embeddingResponse{}, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+m.token.value)
-
 	res, err := m.httpClient.DoWithRetry(req)
 	if err != nil {
 		return embeddingResponse{}, err
 	}
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
 
 	var embeddingRes embeddingResponse
 
-	if err := json.NewDecoder(res.Body).Decode(&embeddingRes); err != nil {
+	if err := decodeJSONStream(m.codec, res, &embeddingRes); err != nil {
 		return embeddingResponse{}, err
 	}
 
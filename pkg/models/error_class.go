@@ -0,0 +1,114 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrorClass is the broad category of an error, used to decide whether it is worth retrying
+type ErrorClass int
+
+const (
+	ErrorClassUnknown     ErrorClass = iota // Not recognized, such as a DNS failure or a cancelled context
+	ErrorClassTransient                     // Temporary failure of the service, such as a 503 or a rejected 200 response
+	ErrorClassRateLimited                   // Too many requests (429)
+	ErrorClassAuth                          // Invalid credentials or missing permissions (401, 403)
+	ErrorClassClient                        // Invalid request (other 4xx), or a *RequestError for a request that could not be built
+	ErrorClassServer                        // Internal error of the service (other 5xx)
+	ErrorClassNetwork                       // Connection failure, such as a timeout, a reset connection or an attempt timeout
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassTransient:
+		return "transient"
+	case ErrorClassRateLimited:
+		return "rate_limited"
+	case ErrorClassAuth:
+		return "auth"
+	case ErrorClassClient:
+		return "client"
+	case ErrorClassServer:
+		return "server"
+	case ErrorClassNetwork:
+		return "network"
+	}
+	return "unknown"
+}
+
+// Retryable reports whether errors of the class may succeed when retried:
+// transient, rate limited, server and network errors.
+func (c ErrorClass) Retryable() bool {
+	switch c {
+	case ErrorClassTransient, ErrorClassRateLimited, ErrorClassServer, ErrorClassNetwork:
+		return true
+	}
+	return false
+}
+
+// ClassifyError returns the class of an error returned by the client, such as a *WatsonxError
+// or a network error. It is used by the default retry condition and can be used by callers
+// to handle errors the same way.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassUnknown
+	}
+
+	// The request was never sent, so it would fail the same way again
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) {
+		return ErrorClassClient
+	}
+
+	// Context errors also implement net.Error, so they are checked first
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrMaxElapsedTime) {
+		return ErrorClassUnknown
+	}
+
+	// An attempt that took too long may succeed on another connection
+	if errors.Is(err, ErrAttemptTimeout) {
+		return ErrorClassNetwork
+	}
+
+	var wxErr *WatsonxError
+	if errors.As(err, &wxErr) {
+		return classifyStatusCode(wxErr.StatusCode)
+	}
+
+	var invalidErr *InvalidResponseError
+	if errors.As(err, &invalidErr) {
+		return ErrorClassTransient
+	}
+
+	if isTransientNetworkError(err) {
+		return ErrorClassNetwork
+	}
+
+	return ErrorClassUnknown
+}
+
+// classifyStatusCode returns the class of an HTTP error status
+func classifyStatusCode(statusCode int) ErrorClass {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorClassAuth
+	case statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusBadGateway ||
+		statusCode == http.StatusServiceUnavailable ||
+		statusCode == http.StatusGatewayTimeout:
+		return ErrorClassTransient
+	case statusCode >= 500:
+		return ErrorClassServer
+	case statusCode >= 400:
+		return ErrorClassClient
+	}
+	return ErrorClassUnknown
+}
+
+// retryOnRetryableClass is the default RetryIfFunc, retrying the errors whose class is retryable
+func retryOnRetryableClass(err error) bool {
+	return ClassifyError(err).Retryable()
+}
@@ -2,30 +2,192 @@ package models
 
 import (
 	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
+var (
+	// ErrQuotaExceeded matches (with errors.Is) a WatsonxError caused by an exhausted usage quota
+	ErrQuotaExceeded = errors.New("watsonx quota exceeded")
+	// ErrPlanLimit matches (with errors.Is) a WatsonxError caused by a limit of the service plan
+	ErrPlanLimit = errors.New("watsonx plan limit reached")
+	// ErrTokenExpired matches (with errors.Is) a WatsonxError caused by an expired or invalid IAM token,
+	// which can be resolved by refreshing the token and retrying once
+	ErrTokenExpired = errors.New("watsonx IAM token expired")
+	// ErrForbidden matches (with errors.Is) a WatsonxError caused by missing permissions on the project or space
+	ErrForbidden = errors.New("watsonx access forbidden")
+)
+
+// DefaultMaxErrorBodySize is the maximum number of bytes read from an error response body
+const DefaultMaxErrorBodySize int64 = 1 << 20 // 1 MiB
+
+// Error codes returned by watsonx when a quota or plan limit is reached.
+// authorization_rejected is also sent for authentication failures, so it only means a quota on a 403.
+var (
+	quotaExceededCodes = []string{"token_quota_reached", "quota_exceeded"}
+	planLimitCodes     = []string{"plan_limit_reached", "plan_limit_exceeded", "unsupported_plan"}
+)
+
+// Error codes returned by watsonx when authentication or authorization fails
+var (
+	tokenExpiredCodes = []string{"authentication_token_expired", "authentication_token_not_valid", "token_expired", "invalid_token"}
+	forbiddenCodes    = []string{"no_associated_service_instance_error", "user_not_authorized", "insufficient_permissions", "forbidden"}
+)
+
+// RequestError is returned when a request cannot be built, such as when its payload cannot be encoded
+// or its URL is malformed. The request was never sent and would fail the same way again, so it is not retried.
+type RequestError struct {
+	Op  string // What failed, such as "encode payload" or "create request"
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("watsonx request: %s: %v", e.Op, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
 // WatsonxError represents a structured WatsonX API error
 type WatsonxError struct {
 	StatusCode int
 	Errors     []ErrorDetail
 	Trace      string
+	RetryAfter time.Duration // Delay requested by the server through the Retry-After header, if any
+	Truncated  bool          // The response body exceeded the read limit and was not fully read
+
+	// AttemptCount is the number of attempts made before the error was returned by DoWithRetry or Retry.
+	// It is zero for errors decoded outside of the retry loop.
+	AttemptCount uint
 }
 
 // Error implements the error interface
 func (e *WatsonxError) Error() string {
+	msg := fmt.Sprintf("watsonx error (%d)", e.StatusCode)
 	if len(e.Errors) > 0 {
-		return fmt.Sprintf(
+		msg = fmt.Sprintf(
 			"watsonx error (%d): %s - %s",
 			e.StatusCode,
 			e.Errors[0].Code,
 			e.Errors[0].Message,
 		)
 	}
-	return fmt.Sprintf("watsonx error (%d)", e.StatusCode)
+	if e.Truncated {
+		msg += " (response body truncated)"
+	}
+	return msg
+}
+
+// Is lets errors.Is match the error against ErrQuotaExceeded, ErrPlanLimit, ErrTokenExpired and ErrForbidden
+// based on the error codes, so callers can route billing and authentication issues rather than retrying.
+func (e *WatsonxError) Is(target error) bool {
+	switch target {
+	case ErrQuotaExceeded:
+		quota := e.hasCode(quotaExceededCodes...) ||
+			(e.StatusCode == http.StatusForbidden && e.hasCode("authorization_rejected"))
+		return quota && !e.isPlanLimitMessage()
+	case ErrPlanLimit:
+		return e.hasCode(planLimitCodes...) || e.isPlanLimitMessage()
+	case ErrTokenExpired:
+		return e.StatusCode == http.StatusUnauthorized && e.hasCode(tokenExpiredCodes...)
+	case ErrForbidden:
+		return e.hasCode(forbiddenCodes...)
+	}
+	return false
+}
+
+// hasCode reports whether any error detail has one of the given codes
+func (e *WatsonxError) hasCode(codes ...string) bool {
+	for _, detail := range e.Errors {
+		for _, code := range codes {
+			if detail.Code == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPlanLimitMessage reports whether a 403 error message mentions a plan limit
+func (e *WatsonxError) isPlanLimitMessage() bool {
+	if e.StatusCode != http.StatusForbidden {
+		return false
+	}
+	for _, detail := range e.Errors {
+		message := strings.ToLower(detail.Message)
+		if strings.Contains(message, "plan") && strings.Contains(message, "limit") {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggestion returns a human-friendly hint on how to resolve the error, followed by the documentation
+// link of the first error detail when there is one. It returns an empty string when there is no hint.
+func (e *WatsonxError) Suggestion() string {
+	suggestion := e.suggestion()
+
+	for _, detail := range e.Errors {
+		if detail.MoreInfo == "" {
+			continue
+		}
+		if suggestion == "" {
+			return "See " + detail.MoreInfo
+		}
+		return suggestion + ". See " + detail.MoreInfo
+	}
+
+	return suggestion
+}
+
+// suggestion returns the hint for the status and error codes
+func (e *WatsonxError) suggestion() string {
+	switch {
+	case e.isContextLengthError():
+		return "Reduce max_new_tokens or shorten the prompt to fit the model's context length"
+	case e.hasCode("model_not_supported"):
+		return "Check the model ID; list the available models with the foundation model specs endpoint"
+	case e.Is(ErrForbidden):
+		return "Check that your API key has access to the project or space"
+	case e.StatusCode == http.StatusUnauthorized:
+		return "Refresh your IAM token or check that your API key is valid"
+	case e.Is(ErrPlanLimit):
+		return "Upgrade your watsonx plan to use this feature"
+	case e.Is(ErrQuotaExceeded):
+		return "Wait for your token quota to reset or increase it"
+	case e.StatusCode == http.StatusForbidden:
+		return "Check that your API key has access to the project or space"
+	case e.StatusCode == http.StatusNotFound:
+		return "Check the model, deployment or endpoint you are calling"
+	case e.StatusCode == http.StatusTooManyRequests:
+		return "Reduce the request rate or retry after a delay"
+	case e.StatusCode >= http.StatusInternalServerError:
+		return "The watsonx service is unavailable; retry later"
+	}
+	return ""
+}
+
+// isContextLengthError reports whether the input and requested tokens exceed the model's context length
+func (e *WatsonxError) isContextLengthError() bool {
+	if e.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	for _, detail := range e.Errors {
+		message := strings.ToLower(detail.Message)
+		if strings.Contains(message, "context length") ||
+			strings.Contains(message, "sequence length") ||
+			strings.Contains(message, "max_new_tokens") {
+			return true
+		}
+	}
+	return false
 }
 
 // WatsonxErrorResponse represents the error response structure from Watson X API
@@ -41,14 +203,24 @@ type ErrorDetail struct {
 	MoreInfo string `json:"more_info"`
 }
 
-// DecodeWatsonxError attempts to parse an HTTP error response and return a structured watsonx error
+// DecodeWatsonxError attempts to parse an HTTP error response and return a structured watsonx error.
+// At most DefaultMaxErrorBodySize bytes of the body are read.
 func DecodeWatsonxError(resp *http.Response) error {
+	return decodeWatsonxError(resp, DefaultMaxErrorBodySize)
+}
+
+// decodeWatsonxError parses an HTTP error response, reading at most maxBodySize bytes of the body
+func decodeWatsonxError(resp *http.Response, maxBodySize int64) error {
 	if resp == nil {
 		return &WatsonxError{}
 	}
 
+	if resp.Body == nil {
+		return newWatsonxError(resp, nil, false, jsonCodec{})
+	}
+
 	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	body, truncated, err := readErrorBody(resp.Body, maxBodySize)
 	if err != nil {
 		return &WatsonxError{
 			StatusCode: resp.StatusCode,
@@ -58,8 +230,31 @@ func DecodeWatsonxError(resp *http.Response) error {
 	// Restore body so it can be read again
 	resp.Body = io.NopCloser(bytes.NewBuffer(body))
 
+	return newWatsonxError(resp, body, truncated, jsonCodec{})
+}
+
+// readErrorBody reads up to maxBodySize bytes of an error response body.
+// truncated reports whether the body was longer; a maxBodySize of zero or less reads the whole body.
+func readErrorBody(r io.Reader, maxBodySize int64) (body []byte, truncated bool, err error) {
+	if maxBodySize <= 0 {
+		body, err = io.ReadAll(r)
+		return body, false, err
+	}
+
+	// Read one byte past the limit to detect longer bodies
+	body, err = io.ReadAll(io.LimitReader(r, maxBodySize+1))
+	if int64(len(body)) > maxBodySize {
+		return body[:maxBodySize], true, err
+	}
+	return body, false, err
+}
+
+// newWatsonxError builds a WatsonxError from an already read error response body, decoded with the codec
+func newWatsonxError(resp *http.Response, body []byte, truncated bool, codec Codec) *WatsonxError {
 	wxErr := &WatsonxError{
 		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Truncated:  truncated,
 	}
 
 	// Empty body → status-only error
@@ -69,7 +264,7 @@ func DecodeWatsonxError(resp *http.Response) error {
 
 	// Try to parse WatsonX error schema
 	var apiErr WatsonxErrorResponse
-	if err := json.Unmarshal(body, &apiErr); err != nil {
+	if err := codec.Unmarshal(body, &apiErr); err != nil {
 		// Unknown / non-JSON error
 		return wxErr
 	}
@@ -79,3 +274,39 @@ func DecodeWatsonxError(resp *http.Response) error {
 
 	return wxErr
 }
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+// Returns 0 if the header is missing or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		// Saturate instead of overflowing into a negative duration
+		if int64(seconds) > math.MaxInt64/int64(time.Second) {
+			return math.MaxInt64
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
+
+// retryAfterFromError returns the Retry-After delay carried by a WatsonxError, if any
+func retryAfterFromError(err error) time.Duration {
+	var wxErr *WatsonxError
+	if errors.As(err, &wxErr) {
+		return wxErr.RetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	TextExtractionEndpoint string = "/ml/v1/text/extractions"
+)
+
+// Statuses of a text extraction job
+const (
+	ExtractionSubmitted   = "submitted"
+	ExtractionUploading   = "uploading"
+	ExtractionRunning     = "running"
+	ExtractionDownloading = "downloading"
+	ExtractionDownloaded  = "downloaded"
+	ExtractionCompleted   = "completed"
+	ExtractionFailed      = "failed"
+)
+
+// ExtractionRequest describes the document to extract text from and where to write the results
+type ExtractionRequest struct {
+	Document      COSReference
+	Results       COSReference
+	OutputFormats []string // e.g. "md", "plain_text", "assembly"; the service default when empty
+}
+
+type ExtractionParameters struct {
+	RequestedOutputs []string `json:"requested_outputs,omitempty"`
+}
+
+type ExtractionPayload struct {
+	ProjectID         string                `json:"project_id,omitempty"`
+	SpaceID           string                `json:"space_id,omitempty"`
+	DocumentReference COSReference          `json:"document_reference"`
+	ResultsReference  COSReference          `json:"results_reference"`
+	Parameters        *ExtractionParameters `json:"parameters,omitempty"`
+}
+
+// ExtractionResponse holds the state of a text extraction job
+type ExtractionResponse struct {
+	ID                string
+	CreatedAt         time.Time
+	Status            string
+	PagesProcessed    int
+	DocumentReference COSReference
+	ResultsReference  COSReference
+	Error             *ErrorDetail // Set when the job failed
+}
+
+// extractionResource is the job resource returned by the extraction endpoints
+type extractionResource struct {
+	Metadata struct {
+		ID        string    `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"metadata"`
+	Entity struct {
+		DocumentReference COSReference `json:"document_reference"`
+		ResultsReference  COSReference `json:"results_reference"`
+		Results           struct {
+			Status               string       `json:"status"`
+			NumberPagesProcessed int          `json:"number_pages_processed"`
+			Error                *ErrorDetail `json:"error,omitempty"`
+		} `json:"results"`
+	} `json:"entity"`
+}
+
+func (r extractionResource) response() *ExtractionResponse {
+	return &ExtractionResponse{
+		ID:                r.Metadata.ID,
+		CreatedAt:         r.Metadata.CreatedAt,
+		Status:            r.Entity.Results.Status,
+		PagesProcessed:    r.Entity.Results.NumberPagesProcessed,
+		DocumentReference: r.Entity.DocumentReference,
+		ResultsReference:  r.Entity.ResultsReference,
+		Error:             r.Entity.Results.Error,
+	}
+}
+
+// ExtractText submits a text extraction job for a document stored in Cloud Object Storage.
+// The job runs asynchronously; use WaitForExtraction to wait for its results.
+func (m *Client) ExtractText(ctx context.Context, req ExtractionRequest) (*ExtractionResponse, error) {
+	m.CheckAndRefreshToken()
+
+	if err := req.Document.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid document reference: %w", err)
+	}
+	if err := req.Results.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid results reference: %w", err)
+	}
+
+	payload := ExtractionPayload{
+		ProjectID:         m.projectID,
+		SpaceID:           m.spaceID,
+		DocumentReference: req.Document,
+		ResultsReference:  req.Results,
+	}
+
+	if len(req.OutputFormats) > 0 {
+		payload.Parameters = &ExtractionParameters{RequestedOutputs: req.OutputFormats}
+	}
+
+	httpReq, err := m.newJSONRequest(ctx, TextExtractionEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.doExtractionRequest(httpReq)
+}
+
+// GetExtraction returns the current state of a text extraction job
+func (m *Client) GetExtraction(ctx context.Context, id string) (*ExtractionResponse, error) {
+	m.CheckAndRefreshToken()
+
+	if id == "" {
+		return nil, errors.New("extraction ID cannot be empty")
+	}
+
+	httpReq, err := m.newGetRequest(ctx, TextExtractionEndpoint+"/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.doExtractionRequest(httpReq)
+}
+
+// WaitForExtraction polls a text extraction job until it completes or fails.
+// Polls are spaced with the retry backoff options (by default 1s with up to 1s of jitter),
+// for example WithExponentialBackoff and WithMaxBackoff; the retry count is ignored.
+func (m *Client) WaitForExtraction(ctx context.Context, id string, options ...RetryOption) (*ExtractionResponse, error) {
+	opts := newDefaultRetryConfig()
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	var extraction *ExtractionResponse
+	fetch := func() (string, error) {
+		var err error
+		extraction, err = m.GetExtraction(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return extraction.Status, nil
+	}
+
+	terminal := []string{ExtractionCompleted, ExtractionFailed}
+	if _, err := poll(ctx, fetch, terminal, opts.timer, func(n uint) time.Duration { return opts.delay(n, nil) }); err != nil {
+		return nil, err
+	}
+
+	if extraction.Status == ExtractionFailed {
+		if extraction.Error != nil {
+			return extraction, fmt.Errorf("extraction %s failed: %s - %s", id, extraction.Error.Code, extraction.Error.Message)
+		}
+		return extraction, fmt.Errorf("extraction %s failed", id)
+	}
+
+	return extraction, nil
+}
+
+// doExtractionRequest sends a request to the extraction endpoints and decodes the job
+func (m *Client) doExtractionRequest(httpReq *http.Request) (*ExtractionResponse, error) {
+	res, err := m.httpClient.DoWithRetry(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+
+	var resource extractionResource
+	if err := decodeJSONStream(m.codec, res, &resource); err != nil {
+		return nil, err
+	}
+
+	return resource.response(), nil
+}
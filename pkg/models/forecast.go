@@ -0,0 +1,156 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	ForecastEndpoint string = "/ml/v1/time_series/forecast"
+)
+
+// ForecastSchema describes the columns of the input data frame
+type ForecastSchema struct {
+	TimestampColumn string   `json:"timestamp_column"`
+	IDColumns       []string `json:"id_columns,omitempty"`
+	TargetColumns   []string `json:"target_columns,omitempty"`
+	Freq            string   `json:"freq,omitempty"` // e.g. "1h", "1D"
+}
+
+// ForecastRequest describes a time series forecast.
+// Data maps each column name to its values, e.g. []string timestamps and []float64 targets.
+type ForecastRequest struct {
+	Model            string
+	Data             map[string]interface{}
+	Schema           ForecastSchema
+	PredictionLength uint
+}
+
+// ForecastParameters holds the forecast parameters
+type ForecastParameters struct {
+	PredictionLength *uint `json:"prediction_length,omitempty"`
+}
+
+type ForecastPayload struct {
+	ProjectID  string                 `json:"project_id,omitempty"`
+	SpaceID    string                 `json:"space_id,omitempty"`
+	Model      string                 `json:"model_id"`
+	Data       map[string]interface{} `json:"data"`
+	Schema     ForecastSchema         `json:"schema"`
+	Parameters *ForecastParameters    `json:"parameters,omitempty"`
+}
+
+// ForecastResponse holds the forecasted data frame
+type ForecastResponse struct {
+	ModelID          string                       `json:"model_id"`
+	CreatedAt        time.Time                    `json:"created_at"`
+	Results          []map[string]json.RawMessage `json:"results"`
+	InputDataPoints  int                          `json:"input_data_points"`
+	OutputDataPoints int                          `json:"output_data_points"`
+
+	// Points are the forecasted values per future timestamp, in order
+	Points []ForecastPoint `json:"-"`
+}
+
+// ForecastPoint holds the forecasted value of each target column at a future timestamp
+type ForecastPoint struct {
+	Timestamp string
+	Values    map[string]float64
+}
+
+// Forecast predicts the future values of a time series
+func (m *Client) Forecast(ctx context.Context, req ForecastRequest) (*ForecastResponse, error) {
+	m.CheckAndRefreshToken()
+
+	if req.Model == "" {
+		return nil, errors.New("model cannot be empty")
+	}
+
+	if req.Schema.TimestampColumn == "" {
+		return nil, errors.New("timestamp column cannot be empty")
+	}
+
+	if len(req.Data) == 0 {
+		return nil, errors.New("data cannot be empty")
+	}
+
+	payload := ForecastPayload{
+		ProjectID: m.projectID,
+		SpaceID:   m.spaceID,
+		Model:     req.Model,
+		Data:      req.Data,
+		Schema:    req.Schema,
+	}
+
+	if req.PredictionLength > 0 {
+		payload.Parameters = &ForecastParameters{PredictionLength: &req.PredictionLength}
+	}
+
+	httpReq, err := m.newJSONRequest(ctx, ForecastEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := m.httpClient.DoWithRetry(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+
+	var forecastRes ForecastResponse
+	if err := decodeJSONStream(m.codec, res, &forecastRes); err != nil {
+		return nil, err
+	}
+
+	points, err := forecastPoints(forecastRes.Results, req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	forecastRes.Points = points
+
+	return &forecastRes, nil
+}
+
+// forecastPoints converts the forecasted columns into one point per timestamp
+func forecastPoints(results []map[string]json.RawMessage, schema ForecastSchema) ([]ForecastPoint, error) {
+	var points []ForecastPoint
+
+	for _, result := range results {
+		var timestamps []string
+		if err := json.Unmarshal(result[schema.TimestampColumn], &timestamps); err != nil {
+			return nil, fmt.Errorf("failed to parse forecast column %q: %w", schema.TimestampColumn, err)
+		}
+
+		targets := map[string][]float64{}
+		for _, column := range schema.TargetColumns {
+			raw, ok := result[column]
+			if !ok {
+				continue
+			}
+
+			var values []float64
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return nil, fmt.Errorf("failed to parse forecast column %q: %w", column, err)
+			}
+			targets[column] = values
+		}
+
+		for i, timestamp := range timestamps {
+			point := ForecastPoint{
+				Timestamp: timestamp,
+				Values:    map[string]float64{},
+			}
+			for column, values := range targets {
+				if i < len(values) {
+					point.Values[column] = values[i]
+				}
+			}
+			points = append(points, point)
+		}
+	}
+
+	return points, nil
+}
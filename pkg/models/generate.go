@@ -1,13 +1,15 @@
 package models
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const (
@@ -16,7 +18,8 @@ const (
 	GenerateTextStreamEndpoint string = GenerationEndpoint + "/generation_stream"
 )
 
-type StopReason = string
+// StopReason tells why the model stopped generating
+type StopReason string
 
 const (
 	NotFinished        StopReason = "not_finished"  // Possibly more tokens to be streamed
@@ -30,33 +33,329 @@ const (
 )
 
 type GenerateTextResult struct {
-	Text                string     `json:"generated_text"`
-	GeneratedTokenCount int        `json:"generated_token_count"`
-	InputTokenCount     int        `json:"input_token_count"`
-	StopReason          StopReason `json:"stop_reason"`
+	Text                string             `json:"generated_text"`
+	GeneratedTokenCount int64              `json:"generated_token_count"`
+	InputTokenCount     int64              `json:"input_token_count"`
+	StopReason          StopReason         `json:"stop_reason"`
+	Moderations         *ModerationResults `json:"moderations,omitempty"`
+
+	// InputText echoes the prompt as seen by the model, after any server-side templating.
+	// It is returned when requested with WithInputText or WithReturnOptions.
+	InputText string `json:"input_text,omitempty"`
+
+	// Per-token details, returned when requested with WithReturnOptions or WithTokenLogProbs
+	GeneratedTokens []GeneratedToken `json:"generated_tokens,omitempty"`
+	InputTokens     []GeneratedToken `json:"input_tokens,omitempty"`
+
+	// MatchedStopSequence is the stop sequence that ended the generation when StopReason is StopSequence.
+	// It is found at the end of the generated text, so it stays empty if the sequence is not included in the text.
+	MatchedStopSequence string `json:"-"`
+}
+
+// TrimmedText returns the generated text without leading and trailing whitespace
+func (r GenerateTextResult) TrimmedText() string {
+	return strings.TrimSpace(r.Text)
+}
+
+// TextWithoutPrompt returns the generated text without the prompt when the model echoed it at the start.
+// The text is returned unchanged when it does not start with the prompt.
+func (r GenerateTextResult) TextWithoutPrompt(prompt string) string {
+	return strings.TrimPrefix(r.Text, prompt)
+}
+
+// TextBeforeStopSequence returns the generated text cut at the earliest of the stop sequences, which is excluded.
+// The text is returned unchanged when it contains none of them.
+func (r GenerateTextResult) TextBeforeStopSequence(stopSequences ...string) string {
+	cut := len(r.Text)
+	for _, stop := range stopSequences {
+		if stop == "" {
+			continue
+		}
+		if i := strings.Index(r.Text[:cut], stop); i >= 0 {
+			cut = i
+		}
+	}
+	return r.Text[:cut]
+}
+
+// GeneratedToken holds the details of a single token.
+// LogProb and Rank are only set when token_logprobs and token_ranks are requested.
+type GeneratedToken struct {
+	Text      string     `json:"text"`
+	LogProb   *float64   `json:"logprob,omitempty"`
+	Rank      *int       `json:"rank,omitempty"`
+	TopTokens []TopToken `json:"top_tokens,omitempty"`
+}
+
+// TopToken is one of the most likely candidates for a token position
+type TopToken struct {
+	Text    string   `json:"text"`
+	LogProb *float64 `json:"logprob,omitempty"`
 }
 
 type GenerateTextPayload struct {
-	ProjectID  string           `json:"project_id"`
-	Model      string           `json:"model_id"`
-	Prompt     string           `json:"input"`
-	Parameters *GenerateOptions `json:"parameters,omitempty"`
+	ProjectID   string           `json:"project_id,omitempty"`
+	SpaceID     string           `json:"space_id,omitempty"`
+	Model       string           `json:"model_id"`
+	Prompt      string           `json:"input"`
+	Parameters  *GenerateOptions `json:"parameters,omitempty"`
+	Moderations *Moderations     `json:"moderations,omitempty"`
+
+	// Prompts are sent as an array input in place of Prompt when set
+	Prompts []string `json:"-"`
+}
+
+// MarshalJSON encodes the input as an array when several prompts are set
+func (p GenerateTextPayload) MarshalJSON() ([]byte, error) {
+	type payload GenerateTextPayload
+	if len(p.Prompts) == 0 {
+		return json.Marshal(payload(p))
+	}
+
+	return json.Marshal(struct {
+		payload
+		Input []string `json:"input"`
+	}{payload(p), p.Prompts})
+}
+
+// UnmarshalJSON decodes an input given either as a single prompt or as an array of prompts
+func (p *GenerateTextPayload) UnmarshalJSON(data []byte) error {
+	type payload GenerateTextPayload
+	var decoded struct {
+		payload
+		Input json.RawMessage `json:"input"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*p = GenerateTextPayload(decoded.payload)
+	if len(decoded.Input) > 0 && decoded.Input[0] == '[' {
+		return json.Unmarshal(decoded.Input, &p.Prompts)
+	}
+	if len(decoded.Input) > 0 {
+		return json.Unmarshal(decoded.Input, &p.Prompt)
+	}
+	return nil
+}
+
+// GenerateTextRequest describes a generation of the given model and prompt
+type GenerateTextRequest struct {
+	Model   string
+	Prompt  string
+	Options []GenerateOption
+
+	// Prompts generates several prompts in a single request, in place of Prompt.
+	// The results of the response follow the order of the prompts.
+	Prompts []string
+}
+
+// validate checks that the request has either a prompt or several non-empty prompts
+func (r GenerateTextRequest) validate() error {
+	if r.Prompt != "" && len(r.Prompts) > 0 {
+		return errors.New("prompt and prompts are mutually exclusive")
+	}
+	if r.Prompt == "" && len(r.Prompts) == 0 {
+		return errors.New("prompt cannot be empty")
+	}
+	for i, prompt := range r.Prompts {
+		if prompt == "" {
+			return fmt.Errorf("prompt %d cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+// GenerateTextResponse holds every result of a generation, e.g. when several are requested with WithNumReturnSequences
+type GenerateTextResponse struct {
+	ModelID   string               `json:"model_id"`
+	CreatedAt time.Time            `json:"created_at"`
+	Results   []GenerateTextResult `json:"results"`
+	System    *SystemDetails       `json:"system,omitempty"`
+
+	// Raw is the full response payload, giving access to fields this package does not map yet
+	Raw json.RawMessage `json:"-"`
+
+	// RateLimit is the rate limit state sent with the response, or nil if the headers were not sent
+	RateLimit *RateLimitInfo `json:"-"`
+}
+
+// FirstText returns the text of the first result, or an empty string if there is none
+func (r GenerateTextResponse) FirstText() string {
+	if len(r.Results) == 0 {
+		return ""
+	}
+	return r.Results[0].Text
+}
+
+// Usage returns the token counts of the response, summed over its results
+func (r GenerateTextResponse) Usage() GenerationUsage {
+	var usage GenerationUsage
+	for _, result := range r.Results {
+		usage.InputTokenCount += result.InputTokenCount
+		usage.GeneratedTokenCount += result.GeneratedTokenCount
+	}
+	return usage
 }
 
 type generateTextResponse struct {
-	Status     string               `json:"status"`
-	StatusCode int                  `json:"status_code"`
-	Results    []GenerateTextResult `json:"results"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	GenerateTextResponse
 }
 
 // GenerateText generates completion text based on a given prompt and parameters
 func (m *Client) GenerateText(model, prompt string, options ...GenerateOption) (GenerateTextResult, error) {
+	return m.generateText(context.Background(), model, prompt, options...)
+}
+
+// generateText generates completion text, cancelling the request when ctx is done
+func (m *Client) generateText(ctx context.Context, model, prompt string, options ...GenerateOption) (GenerateTextResult, error) {
+	response, err := m.Generate(ctx, GenerateTextRequest{
+		Model:   model,
+		Prompt:  prompt,
+		Options: options,
+	})
+	if err != nil {
+		return GenerateTextResult{}, err
+	}
+
+	result := response.Results[0]
+
+	return result, nil
+}
+
+// Complete generates completion text for a prompt and returns only the text of the first result.
+// It is a shortcut for the common single-prompt case, cancelling the request when ctx is done;
+// use Generate to access the token counts, stop reason or other results.
+func (m *Client) Complete(ctx context.Context, model, prompt string, options ...GenerateOption) (string, error) {
+	response, err := m.Generate(ctx, GenerateTextRequest{
+		Model:   model,
+		Prompt:  prompt,
+		Options: options,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return response.FirstText(), nil
+}
+
+// Generate generates completion text and returns every result of the response
+func (m *Client) Generate(ctx context.Context, req GenerateTextRequest) (GenerateTextResponse, error) {
 	m.CheckAndRefreshToken()
 
-	if prompt == "" {
-		return GenerateTextResult{}, errors.New("prompt cannot be empty")
+	if err := req.validate(); err != nil {
+		return GenerateTextResponse{}, err
+	}
+
+	payload := m.newGenerateTextPayload(ctx, req.Model, req.Prompt, req.Options...)
+	payload.Prompts = req.Prompts
+
+	if payload.Parameters.TunedModelID != "" && len(req.Prompts) > 0 {
+		return GenerateTextResponse{}, errors.New("several prompts cannot be sent to a tuned model")
+	}
+
+	if payload.Model == "" && payload.Parameters.TunedModelID == "" {
+		return GenerateTextResponse{}, &RequestError{Op: "select model", Err: errNoModel}
 	}
 
+	var response generateTextResponse
+	var err error
+	if payload.Parameters.TunedModelID != "" {
+		response, err = m.generateFromDeployment(ctx, payload.Parameters.TunedModelID, req.Prompt, payload.Parameters)
+	} else {
+		response, err = m.generateTextRequest(ctx, payload)
+	}
+	if err != nil {
+		return GenerateTextResponse{}, err
+	}
+
+	if len(response.Results) == 0 {
+		return GenerateTextResponse{}, errors.New("no result recieved")
+	}
+
+	// Without several sequences per prompt, every prompt must have its own result to keep them aligned
+	numReturnSequences := payload.Parameters.NumReturnSequences
+	if len(req.Prompts) > 0 && (numReturnSequences == nil || *numReturnSequences <= 1) && len(response.Results) != len(req.Prompts) {
+		return GenerateTextResponse{}, fmt.Errorf("expected %d results, one per prompt, got %d", len(req.Prompts), len(response.Results))
+	}
+
+	if payload.Parameters != nil && payload.Parameters.StopSequences != nil {
+		for i := range response.Results {
+			result := &response.Results[i]
+			if result.StopReason == StopSequence {
+				result.MatchedStopSequence = matchStopSequence(result.Text, *payload.Parameters.StopSequences)
+			}
+		}
+	}
+
+	return response.GenerateTextResponse, nil
+}
+
+// matchStopSequence returns the longest stop sequence the text ends with, or an empty string
+func matchStopSequence(text string, stopSequences []string) string {
+	matched := ""
+	for _, sequence := range stopSequences {
+		if len(sequence) > len(matched) && strings.HasSuffix(text, sequence) {
+			matched = sequence
+		}
+	}
+	return matched
+}
+
+// BuildGenerateRequest builds the fully-formed generation request (URL, headers and body) without sending it,
+// so it can be inspected or snapshotted. Like Generate, it targets the deployment endpoint of a tuned model
+// set with WithTunedModel.
+func (m *Client) BuildGenerateRequest(req GenerateTextRequest) (*http.Request, error) {
+	if err := m.CheckAndRefreshToken(); err != nil {
+		return nil, err
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	payload := m.newGenerateTextPayload(context.Background(), req.Model, req.Prompt, req.Options...)
+	payload.Prompts = req.Prompts
+
+	if tunedModelID := payload.Parameters.TunedModelID; tunedModelID != "" {
+		if len(req.Prompts) > 0 {
+			return nil, errors.New("several prompts cannot be sent to a tuned model")
+		}
+		if err := validateDeploymentID(tunedModelID); err != nil {
+			return nil, err
+		}
+
+		endpoint := fmt.Sprintf(DeploymentGenerateTextEndpointFormat, tunedModelID)
+		return m.newJSONRequest(context.Background(), endpoint, newDeploymentGenerateTextPayload(req.Prompt, payload.Parameters))
+	}
+
+	if payload.Model == "" {
+		return nil, &RequestError{Op: "select model", Err: errNoModel}
+	}
+
+	return m.newJSONRequest(context.Background(), GenerateTextEndpoint, payload)
+}
+
+// newGenerateTextPayload builds the generation payload from the model, prompt and options.
+// Without a model, the default model of the client is used. Without an explicit time limit, the time left before the deadline of ctx is sent as the time limit.
+func (m *Client) newGenerateTextPayload(ctx context.Context, model, prompt string, options ...GenerateOption) GenerateTextPayload {
+	opts := newGenerateOptions(ctx, options...)
+
+	return GenerateTextPayload{
+		ProjectID:   m.projectID,
+		SpaceID:     m.spaceID,
+		Model:       m.modelOrDefault(model),
+		Prompt:      prompt,
+		Parameters:  opts,
+		Moderations: opts.Moderations,
+	}
+}
+
+// newGenerateOptions applies the options. Without an explicit time limit, the time left before the deadline
+// of ctx is set as the time limit, so the server gives up when the client does.
+func newGenerateOptions(ctx context.Context, options ...GenerateOption) *GenerateOptions {
 	opts := &GenerateOptions{}
 	for _, opt := range options {
 		if opt != nil {
@@ -64,61 +363,58 @@ func (m *Client) GenerateText(model, prompt string, options ...GenerateOption) (
 		}
 	}
 
-	payload := GenerateTextPayload{
-		ProjectID:  m.projectID,
-		Model:      model,
-		Prompt:     prompt,
-		Parameters: opts,
+	if opts.TimeLimit == nil {
+		opts.TimeLimit = timeLimitFromContext(ctx)
 	}
 
-	response, err := m.generateTextRequest(payload)
-	if err != nil {
-		return GenerateTextResult{}, err
-	}
+	return opts
+}
 
-	if len(response.Results) == 0 {
-		return GenerateTextResult{}, errors.New("no result recieved")
+// timeLimitFromContext returns the milliseconds left before the deadline of ctx, or nil without a deadline
+func timeLimitFromContext(ctx context.Context) *uint {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
 	}
 
-	result := response.Results[0]
+	remaining := time.Until(deadline).Milliseconds()
+	if remaining <= 0 {
+		return nil
+	}
 
-	return result, nil
+	timeLimit := uint(remaining)
+	return &timeLimit
 }
 
 // generateTextRequest sends the generate request and handles the response using the http package.
 // Returns error on non-2XX response
-func (m *Client) generateTextRequest(payload GenerateTextPayload) (generateTextResponse, error) {
-	textUrl := m.generateUrlFromEndpoint(GenerateTextEndpoint)
-
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		return generateTextResponse{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, textUrl, bytes.NewBuffer(payloadJSON))
+func (m *Client) generateTextRequest(ctx context.Context, payload GenerateTextPayload) (generateTextResponse, error) {
+	req, err := m.newJSONRequest(ctx, GenerateTextEndpoint, payload)
 	if err != nil {
 		return generateTextResponse{}, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+m.token.value)
-
 	res, err := m.httpClient.DoWithRetry(req)
 	if err != nil {
 		return generateTextResponse{}, err
 	}
-	defer res.Body.Close()
+	defer drainAndClose(res.Body)
 
 	var generateRes generateTextResponse
 
-	if err := json.NewDecoder(res.Body).Decode(&generateRes); err != nil {
+	raw, err := decodeJSONBody(m.codec, res, &generateRes)
+	if err != nil {
 		return generateTextResponse{}, err
 	}
+	generateRes.Raw = raw
+	generateRes.RateLimit = ParseRateLimitInfo(res.Header)
+	m.logWarnings(generateRes.System)
 
 	return generateRes, nil
 }
 
 // GenerateTextStream generates completion text channel (stream) based on a given prompt and parameters
+// If any error happens during the streaming, it will be logged and the channel will be closed
 func (m *Client) GenerateTextStream(model, prompt string, options ...GenerateOption) (<-chan GenerateTextResult, error) {
 	dataChan := make(chan GenerateTextResult)
 
@@ -130,84 +426,22 @@ func (m *Client) GenerateTextStream(model, prompt string, options ...GenerateOpt
 	go func() {
 		defer close(dataChan)
 
-		m.CheckAndRefreshToken()
-
-		opts := &GenerateOptions{}
-		for _, opt := range options {
-			if opt != nil {
-				opt(opts)
-			}
-		}
-
-		payload := GenerateTextPayload{
-			ProjectID:  m.projectID,
-			Model:      model,
-			Prompt:     prompt,
-			Parameters: opts,
-		}
-
-		responseChan, _ := m.generateTextStreamRequest(payload)
-
-		for data := range responseChan {
-			for _, result := range data.Results {
-				dataChan <- result
-			}
-		}
-	}()
-
-	return dataChan, nil
-}
-
-// generateTextStreamRequest sends the generate request and handles the response using the http package.
-// Closes the channel on non-200 response
-// If any error happens during the streaming, it will be logged and the channel will be closed
-func (m *Client) generateTextStreamRequest(payload GenerateTextPayload) (<-chan generateTextResponse, error) {
-	dataChan := make(chan generateTextResponse)
-
-	go func() {
-		defer close(dataChan)
-
-		streamUrl := m.generateUrlFromEndpoint(GenerateTextStreamEndpoint)
-
-		payloadJSON, err := json.Marshal(payload)
-		if err != nil {
-			log.Println("error marshalling payload: ", err)
-			return
-		}
-
-		req, err := http.NewRequest(http.MethodPost, streamUrl, bytes.NewBuffer(payloadJSON))
-		if err != nil {
-			log.Println("error creating request: ", err)
-			return
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+m.token.value)
-		req.Header.Set("Accept", "text/event-stream")
-
-		res, err := m.httpClient.DoWithRetry(req)
+		stream, err := m.StreamGenerateText(context.Background(), model, prompt, options...)
 		if err != nil {
 			log.Println("error making request: ", err)
 			return
 		}
-
-		defer res.Body.Close()
-		scanner := bufio.NewScanner(res.Body)
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if !strings.HasPrefix(line, "data: ") {
-				continue
-			}
-
-			data := line[6:]
-			var generation generateTextResponse
-
-			if err := json.Unmarshal([]byte(data), &generation); err != nil {
-				log.Println("error unmarshalling data: ", err)
+		defer stream.Close()
+
+		for {
+			result, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					log.Println("error reading stream: ", err)
+				}
 				return
 			}
-			dataChan <- generation
+			dataChan <- result
 		}
 	}()
 
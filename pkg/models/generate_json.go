@@ -0,0 +1,51 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// JSONDecodeError is returned by GenerateJSON when the generated text is not valid JSON for the target
+type JSONDecodeError struct {
+	Text string // Raw generated text
+	Err  error
+}
+
+func (e *JSONDecodeError) Error() string {
+	return fmt.Sprintf("failed to decode generated JSON: %v", e.Err)
+}
+
+func (e *JSONDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// GenerateJSON generates text in JSON mode and unmarshals it into out with the codec of the client.
+// A response format set in the request options (e.g. WithJSONSchema) replaces the default JSON object mode.
+// With several Prompts, out must point to a slice, which receives the output of each prompt in order.
+func (m *Client) GenerateJSON(ctx context.Context, req GenerateTextRequest, out interface{}) error {
+	req.Options = append([]GenerateOption{WithJSONMode()}, req.Options...)
+
+	response, err := m.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	text := response.FirstText()
+	if len(req.Prompts) > 0 {
+		texts := make([]string, len(response.Results))
+		for i, result := range response.Results {
+			texts[i] = result.Text
+		}
+		text = "[" + strings.Join(texts, ",") + "]"
+	}
+
+	if err := m.codec.Unmarshal([]byte(text), out); err != nil {
+		return &JSONDecodeError{
+			Text: text,
+			Err:  err,
+		}
+	}
+
+	return nil
+}
@@ -22,19 +22,36 @@ type ReturnOptions struct {
 
 type GenerateOptions struct {
 	// https://ibm.github.io/watson-machine-learning-sdk/_modules/metanames.html#GenTextParamsMetaNames
-	DecodingMethod      *string        `json:"decoding_method,omitempty"`
-	LengthPenalty       *LengthPenalty `json:"length_penalty,omitempty"`
-	Temperature         *float64       `json:"temperature,omitempty"`
-	TopP                *float64       `json:"top_p,omitempty"`
-	TopK                *uint          `json:"top_k,omitempty"`
-	RandomSeed          *uint          `json:"random_seed,omitempty"`
-	RepetitionPenalty   *float64       `json:"repetition_penalty,omitempty"`
-	MinNewTokens        *uint          `json:"min_new_tokens,omitempty"`
-	MaxNewTokens        *uint          `json:"max_new_tokens,omitempty"`
-	StopSequences       *[]string      `json:"stop_sequences,omitempty"`
-	TimeLimit           *uint          `json:"time_limit,omitempty"`
-	TruncateInputTokens *uint          `json:"truncate_input_tokens,omitempty"`
-	ReturnOptions       *ReturnOptions `json:"return_options,omitempty"`
+	DecodingMethod      *string             `json:"decoding_method,omitempty"`
+	LengthPenalty       *LengthPenalty      `json:"length_penalty,omitempty"`
+	Temperature         *float64            `json:"temperature,omitempty"`
+	TopP                *float64            `json:"top_p,omitempty"`
+	TopK                *uint               `json:"top_k,omitempty"`
+	RandomSeed          *uint               `json:"random_seed,omitempty"`
+	RepetitionPenalty   *float64            `json:"repetition_penalty,omitempty"`
+	MinNewTokens        *uint               `json:"min_new_tokens,omitempty"`
+	MaxNewTokens        *uint               `json:"max_new_tokens,omitempty"`
+	StopSequences       *[]string           `json:"stop_sequences,omitempty"`
+	IncludeStopSequence *bool               `json:"include_stop_sequence,omitempty"`
+	TimeLimit           *uint               `json:"time_limit,omitempty"`
+	TruncateInputTokens *uint               `json:"truncate_input_tokens,omitempty"`
+	ReturnOptions       *ReturnOptions      `json:"return_options,omitempty"`
+	ResponseFormat      *ChatResponseFormat `json:"response_format,omitempty"`
+	NumReturnSequences  *uint               `json:"num_return_sequences,omitempty"`
+
+	// Sent at the top level of the request rather than in parameters
+	Moderations *Moderations `json:"-"`
+
+	// Routes the request to a tuned model deployment instead of a base model
+	TunedModelID string `json:"-"`
+}
+
+// WithTunedModel targets a prompt-tuned or custom model by the ID of its deployment asset.
+// The request is sent to the deployment generation endpoint and the base model ID is ignored.
+func WithTunedModel(assetID string) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.TunedModelID = assetID
+	}
 }
 
 func WithDecodingMethod(decodingMethod string) GenerateOption {
@@ -97,6 +114,15 @@ func WithStopSequences(stopSequences []string) GenerateOption {
 	}
 }
 
+// WithIncludeStopSequence sets whether the matched stop sequence is kept at the end of the generated text
+func WithIncludeStopSequence(include bool) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.IncludeStopSequence = &include
+	}
+}
+
+// WithTimeLimit bounds the generation time on the server, in milliseconds.
+// When it is not set, the time left before the deadline of the request context is used.
 func WithTimeLimit(timeLimit uint) GenerateOption {
 	return func(opts *GenerateOptions) {
 		opts.TimeLimit = &timeLimit
@@ -115,6 +141,145 @@ func WithReturnOptions(inputText, generatedTokens, inputTokens, tokenLogProbs, t
 	}
 }
 
+// WithTokenLogProbs returns each generated token with its log probability and rank,
+// along with the topNTokens most likely candidates for each position when topNTokens is positive.
+func WithTokenLogProbs(topNTokens int) GenerateOption {
+	return func(opts *GenerateOptions) {
+		if opts.ReturnOptions == nil {
+			opts.ReturnOptions = &ReturnOptions{}
+		}
+		opts.ReturnOptions.GeneratedTokens = true
+		opts.ReturnOptions.TokenLogProbs = true
+		opts.ReturnOptions.TokenRanks = true
+		opts.ReturnOptions.TopNTokens = topNTokens
+	}
+}
+
+// WithInputText echoes the prompt in the input text of each result, to correlate the output with the exact input
+func WithInputText() GenerateOption {
+	return func(opts *GenerateOptions) {
+		if opts.ReturnOptions == nil {
+			opts.ReturnOptions = &ReturnOptions{}
+		}
+		opts.ReturnOptions.InputText = true
+	}
+}
+
+// WithNumReturnSequences sets the number of candidate results to generate
+func WithNumReturnSequences(numReturnSequences uint) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.NumReturnSequences = &numReturnSequences
+	}
+}
+
+// WithJSONMode constrains the generated text to a JSON object
+func WithJSONMode() GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.ResponseFormat = &ChatResponseFormat{Type: "json_object"}
+	}
+}
+
+// WithJSONSchema constrains the generated text to the given JSON schema
+func WithJSONSchema(schema interface{}) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.ResponseFormat = &ChatResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: schema,
+		}
+	}
+}
+
+// WithModerations sets the HAP and PII moderations of the generation
+func WithModerations(moderations Moderations) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.Moderations = &moderations
+	}
+}
+
+// WithHAPModeration enables HAP moderation on the input and output with the given threshold (0 to 1)
+func WithHAPModeration(threshold float64) GenerateOption {
+	return func(opts *GenerateOptions) {
+		if opts.Moderations == nil {
+			opts.Moderations = &Moderations{}
+		}
+		opts.Moderations.HAP = newModerationOptions(threshold)
+	}
+}
+
+// WithPIIModeration enables PII moderation on the input and output with the given threshold (0 to 1)
+func WithPIIModeration(threshold float64) GenerateOption {
+	return func(opts *GenerateOptions) {
+		if opts.Moderations == nil {
+			opts.Moderations = &Moderations{}
+		}
+		opts.Moderations.PII = newModerationOptions(threshold)
+	}
+}
+
+// Merge returns a copy of the options with every field set in override applied on top.
+// Fields left nil or empty in override keep their current value, so a set of defaults
+// can be combined with per-request overrides without zero values clobbering them.
+func (gp GenerateOptions) Merge(override GenerateOptions) GenerateOptions {
+	merged := gp
+
+	if override.DecodingMethod != nil {
+		merged.DecodingMethod = override.DecodingMethod
+	}
+	if override.LengthPenalty != nil {
+		merged.LengthPenalty = override.LengthPenalty
+	}
+	if override.Temperature != nil {
+		merged.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		merged.TopP = override.TopP
+	}
+	if override.TopK != nil {
+		merged.TopK = override.TopK
+	}
+	if override.RandomSeed != nil {
+		merged.RandomSeed = override.RandomSeed
+	}
+	if override.RepetitionPenalty != nil {
+		merged.RepetitionPenalty = override.RepetitionPenalty
+	}
+	if override.MinNewTokens != nil {
+		merged.MinNewTokens = override.MinNewTokens
+	}
+	if override.MaxNewTokens != nil {
+		merged.MaxNewTokens = override.MaxNewTokens
+	}
+	if override.StopSequences != nil {
+		merged.StopSequences = override.StopSequences
+	}
+	if override.IncludeStopSequence != nil {
+		merged.IncludeStopSequence = override.IncludeStopSequence
+	}
+	if override.TimeLimit != nil {
+		merged.TimeLimit = override.TimeLimit
+	}
+	if override.TruncateInputTokens != nil {
+		merged.TruncateInputTokens = override.TruncateInputTokens
+	}
+	if override.ReturnOptions != nil {
+		merged.ReturnOptions = override.ReturnOptions
+	}
+	if override.ResponseFormat != nil {
+		merged.ResponseFormat = override.ResponseFormat
+	}
+	if override.NumReturnSequences != nil {
+		merged.NumReturnSequences = override.NumReturnSequences
+	}
+	if override.Moderations != nil {
+		merged.Moderations = override.Moderations
+	}
+	if override.TunedModelID != "" {
+		merged.TunedModelID = override.TunedModelID
+	}
+
+	return merged
+}
+
 func (gp *GenerateOptions) String() string {
 	return fmt.Sprintf(
 		"decodingMethod: %v\n"+
@@ -127,9 +292,12 @@ func (gp *GenerateOptions) String() string {
 			"minNewTokens: %v\n"+
 			"maxNewTokens: %v\n"+
 			"stopSequences: %v\n"+
+			"includeStopSequence: %v\n"+
 			"timeLimit: %v\n"+
 			"truncateInputTokens: %v\n"+
-			"returnOptions: %v",
+			"returnOptions: %v\n"+
+			"responseFormat: %v\n"+
+			"numReturnSequences: %v",
 		gp.DecodingMethod,
 		gp.LengthPenalty,
 		gp.Temperature,
@@ -140,8 +308,11 @@ func (gp *GenerateOptions) String() string {
 		gp.MinNewTokens,
 		gp.MaxNewTokens,
 		gp.StopSequences,
+		gp.IncludeStopSequence,
 		gp.TimeLimit,
 		gp.TruncateInputTokens,
 		gp.ReturnOptions,
+		gp.ResponseFormat,
+		gp.NumReturnSequences,
 	)
 }
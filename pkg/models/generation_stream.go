@@ -0,0 +1,172 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// GenerationUsage holds the token counts of a generation
+type GenerationUsage struct {
+	GeneratedTokenCount int64 `json:"generated_token_count"`
+	InputTokenCount     int64 `json:"input_token_count"`
+}
+
+// GenerationStream reads the chunks of a streamed text generation.
+// It also implements io.Reader over the generated text, so it can be copied to any writer.
+type GenerationStream struct {
+	ctx     context.Context
+	codec   Codec
+	body    io.ReadCloser
+	events  *sseReader
+	pending []GenerateTextResult
+	text    []byte // generated text received but not yet returned by Read
+	usage   GenerationUsage
+	err     error
+}
+
+// StreamGenerateText starts a streamed generation and returns a stream to read the chunks from.
+// The caller must Close the stream once done.
+func (m *Client) StreamGenerateText(ctx context.Context, model, prompt string, options ...GenerateOption) (*GenerationStream, error) {
+	m.CheckAndRefreshToken()
+
+	if prompt == "" {
+		return nil, errors.New("prompt cannot be empty")
+	}
+
+	payload := m.newGenerateTextPayload(ctx, model, prompt, options...)
+	if payload.Model == "" {
+		return nil, &RequestError{Op: "select model", Err: errNoModel}
+	}
+
+	req, err := m.newJSONRequest(ctx, GenerateTextStreamEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := m.httpClient.DoWithRetry(req)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := newGenerationStream(res.Body, m.maxStreamEventSize)
+	stream.ctx = ctx
+	stream.codec = m.codec
+
+	return stream, nil
+}
+
+func newGenerationStream(body io.ReadCloser, maxEventSize int) *GenerationStream {
+	return &GenerationStream{
+		codec:  jsonCodec{},
+		body:   body,
+		events: newSSEReader(body, maxEventSize),
+	}
+}
+
+// Recv returns the next generated chunk, or io.EOF once the stream has ended
+func (s *GenerationStream) Recv() (GenerateTextResult, error) {
+	for len(s.pending) == 0 {
+		if s.err != nil {
+			return GenerateTextResult{}, s.err
+		}
+		s.err = s.readEvent()
+	}
+
+	result := s.pending[0]
+	s.pending = s.pending[1:]
+
+	return result, nil
+}
+
+// Read reads the generated text of the stream as it arrives, returning io.EOF once the stream has ended.
+// It fails with the context error once the context of the stream is done.
+// Read and Recv consume the same chunks, so a stream should be read with only one of them.
+func (s *GenerationStream) Read(p []byte) (int, error) {
+	for len(s.text) == 0 {
+		if s.ctx != nil {
+			if err := s.ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+
+		result, err := s.Recv()
+		if err != nil {
+			return 0, err
+		}
+		s.text = []byte(result.Text)
+	}
+
+	n := copy(p, s.text)
+	s.text = s.text[n:]
+
+	return n, nil
+}
+
+// generationStreamFrame is the data of a stream event, which carries either results or errors
+type generationStreamFrame struct {
+	generateTextResponse
+	Errors []ErrorDetail `json:"errors"`
+	Trace  string        `json:"trace"`
+}
+
+// readEvent reads the next data event and queues its results.
+// An error event, or a data frame carrying errors, stops the stream with a *WatsonxError.
+func (s *GenerationStream) readEvent() error {
+	event, err := s.events.Next()
+	if err != nil {
+		return err
+	}
+
+	var frame generationStreamFrame
+	if err := s.codec.Unmarshal([]byte(event.Data), &frame); err != nil {
+		if event.Event == "error" {
+			return &WatsonxError{StatusCode: http.StatusInternalServerError}
+		}
+		return err
+	}
+
+	if event.Event == "error" || len(frame.Errors) > 0 {
+		statusCode := frame.StatusCode
+		if statusCode == 0 {
+			// The stream already answered 200, so report the failure as a server error
+			statusCode = http.StatusInternalServerError
+		}
+		return &WatsonxError{
+			StatusCode: statusCode,
+			Errors:     frame.Errors,
+			Trace:      frame.Trace,
+		}
+	}
+
+	for _, result := range frame.Results {
+		s.recordUsage(result)
+	}
+	s.pending = append(s.pending, frame.Results...)
+
+	return nil
+}
+
+// recordUsage updates the usage from a chunk.
+// watsonx reports running totals in every chunk, so the latest (highest) values are kept.
+func (s *GenerationStream) recordUsage(result GenerateTextResult) {
+	if result.GeneratedTokenCount > s.usage.GeneratedTokenCount {
+		s.usage.GeneratedTokenCount = result.GeneratedTokenCount
+	}
+	if result.InputTokenCount > s.usage.InputTokenCount {
+		s.usage.InputTokenCount = result.InputTokenCount
+	}
+}
+
+// Usage returns the token counts received so far; they are final once Recv has returned io.EOF
+func (s *GenerationStream) Usage() GenerationUsage {
+	return s.usage
+}
+
+// Close releases the underlying connection
+func (s *GenerationStream) Close() error {
+	return s.body.Close()
+}
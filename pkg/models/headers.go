@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"net/http"
+)
+
+// sensitiveHeaders are redacted by RedactHeaders
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+type headersContextKey struct{}
+
+// ContextWithHeaders returns a context carrying per-call headers for requests made with it.
+// They override client default headers of the same name, except Authorization, which is always the bearer token.
+func ContextWithHeaders(ctx context.Context, headers http.Header) context.Context {
+	merged := headersFromContext(ctx).Clone()
+	if merged == nil {
+		merged = http.Header{}
+	}
+	for name, values := range headers {
+		merged[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	return context.WithValue(ctx, headersContextKey{}, merged)
+}
+
+// headersFromContext returns the per-call headers set with ContextWithHeaders
+func headersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(headersContextKey{}).(http.Header)
+	return headers
+}
+
+// RedactHeaders returns a copy of headers with credentials replaced by "[REDACTED]". The client does not log
+// headers itself; call it from a logging Middleware before writing request headers out.
+func RedactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := redacted[name]; ok {
+			redacted[name] = []string{"[REDACTED]"}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// hedgeResult is the outcome of one of the copies of a hedged request
+type hedgeResult struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+// cancelOnClose cancels the context of a winning hedged request once its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// isIdempotentRequest reports whether sending the request more than once is safe:
+// GET, HEAD and OPTIONS requests, and requests carrying an Idempotency-Key.
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// doHedged sends the request and, when hedging is enabled and the request is idempotent,
+// sends up to maxHedges more copies spaced by hedgeDelay while no response has arrived.
+// The first response wins and the other copies are cancelled. If every copy fails, the first error is returned.
+func (c *HttpClient) doHedged(req *http.Request, getBody func() io.ReadCloser, reusableBody bool) (*http.Response, error) {
+	if c.hedgeDelay <= 0 || c.maxHedges <= 0 || !reusableBody || !isIdempotentRequest(req) {
+		req.Body = getBody()
+		return c.httpClient.Do(req)
+	}
+
+	results := make(chan hedgeResult, c.maxHedges+1)
+	var cancels []context.CancelFunc
+
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		hedge := req.Clone(ctx)
+		hedge.Body = getBody()
+
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+
+		go func() {
+			resp, err := c.httpClient.Do(hedge)
+			results <- hedgeResult{index, resp, err}
+		}()
+	}
+
+	send()
+	inFlight := 1
+
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+	hedgeTimer := timer.C
+
+	var firstErr error
+	for {
+		select {
+		case <-hedgeTimer:
+			send()
+			inFlight++
+			if len(cancels) > c.maxHedges {
+				hedgeTimer = nil
+			} else {
+				timer.Reset(c.hedgeDelay)
+			}
+
+		case result := <-results:
+			inFlight--
+
+			if result.err != nil {
+				cancels[result.index]()
+				if firstErr == nil {
+					firstErr = result.err
+				}
+				if inFlight == 0 {
+					return nil, firstErr
+				}
+				continue
+			}
+
+			// Cancel the losers and release the responses they may still return
+			for i, cancel := range cancels {
+				if i != result.index {
+					cancel()
+				}
+			}
+			go discardHedgeResults(results, inFlight)
+
+			result.resp.Body = cancelOnClose{result.resp.Body, cancels[result.index]}
+			return result.resp, nil
+		}
+	}
+}
+
+// discardHedgeResults closes the responses of the n cancelled copies still in flight
+func discardHedgeResults(results <-chan hedgeResult, n int) {
+	for ; n > 0; n-- {
+		if result := <-results; result.resp != nil {
+			drainAndClose(result.resp.Body)
+		}
+	}
+}
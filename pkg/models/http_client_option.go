@@ -0,0 +1,224 @@
+package models
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HttpClientOption is a function type for modifying HttpClient options
+type HttpClientOption func(*HttpClient)
+
+// WithTransport sets the http.RoundTripper used to send requests
+func WithTransport(transport http.RoundTripper) HttpClientOption {
+	return func(c *HttpClient) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// WithRetryOptions sets the retry options used by DoWithRetry
+func WithRetryOptions(options ...RetryOption) HttpClientOption {
+	return func(c *HttpClient) {
+		c.retryOptions = append(c.retryOptions, options...)
+	}
+}
+
+// WithCircuitBreaker short-circuits DoWithRetry with ErrCircuitOpen after failureThreshold
+// consecutive failures, probing the service again once resetTimeout has elapsed.
+func WithCircuitBreaker(failureThreshold int, resetTimeout time.Duration) HttpClientOption {
+	return func(c *HttpClient) {
+		c.circuitBreaker = NewCircuitBreaker(failureThreshold, resetTimeout)
+	}
+}
+
+// WithIdempotencyKey attaches an Idempotency-Key header to requests sent through DoWithRetry.
+// The key is generated once per request and reused by every retry attempt; a key already set by the caller is kept.
+func WithIdempotencyKey() HttpClientOption {
+	return func(c *HttpClient) {
+		c.idempotencyKey = true
+	}
+}
+
+// WithRetryStats aggregates the attempts, retries and failures of DoWithRetry into stats
+func WithRetryStats(stats *RetryStats) HttpClientOption {
+	return func(c *HttpClient) {
+		c.retryStats = stats
+	}
+}
+
+// WithMaxRetryBodySize limits how much of a request body DoWithRetry buffers to replay it on retries.
+// Requests with a larger body are streamed without buffering and sent in a single attempt.
+// A size of zero or less buffers bodies of any size.
+func WithMaxRetryBodySize(size int64) HttpClientOption {
+	return func(c *HttpClient) {
+		c.maxBodySize = size
+	}
+}
+
+// WithMiddleware wraps the transport with the middlewares; the first middleware runs outermost.
+// Middlewares wrap each attempt, so a retried request passes through them again.
+// They are applied around the transport set with WithTransport, regardless of the option order.
+func WithMiddleware(middlewares ...Middleware) HttpClientOption {
+	return func(c *HttpClient) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithDialTimeout limits how long establishing a TCP connection may take.
+// Like the other transport timeouts, it does not bound reading the response body, so streams stay open.
+func WithDialTimeout(timeout time.Duration) HttpClientOption {
+	return func(c *HttpClient) {
+		c.dialTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout limits how long the TLS handshake may take
+func WithTLSHandshakeTimeout(timeout time.Duration) HttpClientOption {
+	return func(c *HttpClient) {
+		c.tlsHandshakeTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout limits how long to wait for the response headers once the request is sent.
+// The response body, such as a generation stream, can take longer.
+func WithResponseHeaderTimeout(timeout time.Duration) HttpClientOption {
+	return func(c *HttpClient) {
+		c.responseHeaderTimeout = timeout
+	}
+}
+
+// WithConnectionTrace reports the DNS, connect, TLS and time-to-first-byte timings of every attempt,
+// along with whether the connection was reused. The callback may be called concurrently.
+func WithConnectionTrace(report ConnectionTraceFunc) HttpClientOption {
+	return func(c *HttpClient) {
+		if report != nil {
+			c.middlewares = append(c.middlewares, connectionTraceMiddleware(report))
+		}
+	}
+}
+
+// WithHTTP2 forces HTTP/2 when enabled, even with a custom TLS configuration, or restricts the client to HTTP/1.1 when disabled.
+// HTTP/2 multiplexes concurrent requests and streams over a single connection.
+func WithHTTP2(enabled bool) HttpClientOption {
+	return func(c *HttpClient) {
+		c.http2 = &enabled
+	}
+}
+
+// WithMaxIdleConnsPerHost sets how many idle connections are kept per host, 2 by default.
+// Raise it for highly concurrent workloads, such as embedding many batches in parallel.
+func WithMaxIdleConnsPerHost(n int) HttpClientOption {
+	return func(c *HttpClient) {
+		c.maxIdleConnsPerHost = n
+	}
+}
+
+// WithProxyURL routes every request through the given HTTP or HTTPS proxy, such as "http://proxy.internal:3128",
+// ignoring the HTTP_PROXY and HTTPS_PROXY environment variables. An empty URL disables proxying.
+// If the URL is invalid, requests fail with the parse error.
+func WithProxyURL(raw string) HttpClientOption {
+	return func(c *HttpClient) {
+		c.proxySet = true
+		if raw == "" {
+			c.proxy = nil
+			return
+		}
+
+		proxyURL, err := url.Parse(raw)
+		if err == nil && proxyURL.Host == "" {
+			err = errors.New("missing host")
+		}
+		if err != nil {
+			err = fmt.Errorf("invalid proxy URL %q: %w", raw, err)
+			c.proxy = func(*http.Request) (*url.URL, error) { return nil, err }
+			return
+		}
+		c.proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithProxyFromEnvironment uses the proxy set by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+// when enabled, or sends requests directly when disabled, whatever the transport was configured with.
+func WithProxyFromEnvironment(enabled bool) HttpClientOption {
+	return func(c *HttpClient) {
+		c.proxySet = true
+		c.proxy = nil
+		if enabled {
+			c.proxy = http.ProxyFromEnvironment
+		}
+	}
+}
+
+// WithClientCertificate presents the certificate to servers that require mutual TLS, such as on-premises gateways.
+// It can be set several times to offer more than one certificate.
+func WithClientCertificate(cert tls.Certificate) HttpClientOption {
+	return func(c *HttpClient) {
+		c.clientCertificates = append(c.clientCertificates, cert)
+	}
+}
+
+// WithRootCAs verifies server certificates against the given pool instead of the system roots,
+// for example to trust the private certificate authority of an on-premises deployment.
+func WithRootCAs(pool *x509.CertPool) HttpClientOption {
+	return func(c *HttpClient) {
+		c.rootCAs = pool
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server TLS certificate, to reach a gateway with a
+// self-signed certificate in development. It must never be used in production: a warning is logged with the
+// logger set by WithLogger when the client is created. Prefer WithRootCAs to trust a private certificate authority.
+func WithInsecureSkipVerify() HttpClientOption {
+	return func(c *HttpClient) {
+		c.insecureSkipVerify = true
+	}
+}
+
+// WithLogger sets the logger that receives the warnings of the client, such as the one of WithInsecureSkipVerify.
+// A nil logger uses the standard logger, the default.
+func WithLogger(logger *log.Logger) HttpClientOption {
+	return func(c *HttpClient) {
+		c.logger = logger
+	}
+}
+
+// RequestInterceptorFunc mutates a request right before it is sent; an error aborts the request
+type RequestInterceptorFunc func(req *http.Request) error
+
+// WithRequestInterceptor runs the interceptors on the final request before every attempt of DoWithRetry,
+// after the SDK has built it, e.g. to add a header or a query parameter. Each attempt gets a fresh copy of
+// the request, so per-attempt state such as a signature can be set. An interceptor error aborts the request
+// with a *RequestError, which is not retried.
+func WithRequestInterceptor(interceptors ...RequestInterceptorFunc) HttpClientOption {
+	return func(c *HttpClient) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithHedging reduces tail latency by sending up to maxHedges more copies of a request, one every delay,
+// while no response has arrived. The first response wins and the other copies are cancelled.
+// Only idempotent requests are hedged: GET requests and requests carrying an Idempotency-Key,
+// so enable WithIdempotencyKey to hedge generation requests.
+func WithHedging(delay time.Duration, maxHedges int) HttpClientOption {
+	return func(c *HttpClient) {
+		c.hedgeDelay = delay
+		c.maxHedges = maxHedges
+	}
+}
+
+// WithMaxConcurrency caps the number of requests sent at the same time through Do and DoWithRetry to n.
+// Further requests wait for a slot, or fail with the error of their context when it is done first.
+// A request holds its slot until its response headers are received, across retries and hedged attempts.
+func WithMaxConcurrency(n int) HttpClientOption {
+	return func(c *HttpClient) {
+		c.limiter = nil
+		if n > 0 {
+			c.limiter = newConcurrencyLimiter(n)
+		}
+	}
+}
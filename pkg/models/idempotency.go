@@ -0,0 +1,23 @@
+package models
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// newIdempotencyKey generates a random UUID (version 4) used to identify a logical request across retries
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MetricsRecorder receives counters and latencies from the retry mechanism
+type MetricsRecorder interface {
+	// IncAttempt is called before every attempt, including the first one
+	IncAttempt()
+	// IncRetry is called each time a failed attempt is going to be retried
+	IncRetry()
+	// ObserveLatency is called after every attempt with the response status code (0 if no response was received)
+	ObserveLatency(status int, d time.Duration)
+}
+
+// StatusClassRecorder can be implemented by a MetricsRecorder to also receive the latency of every attempt
+// labelled with the StatusClass of its status, a low-cardinality alternative to the exact status code
+type StatusClassRecorder interface {
+	ObserveLatencyByClass(class string, d time.Duration)
+}
+
+// StatusClass returns a low-cardinality label for a status code: "1xx" to "5xx", "429" which is kept apart
+// from the other client errors, "error" when no response was received (0) and "unknown" otherwise
+func StatusClass(code int) string {
+	switch {
+	case code == 0:
+		return "error"
+	case code == http.StatusTooManyRequests:
+		return "429"
+	case code >= 100 && code < 600:
+		return strconv.Itoa(code/100) + "xx"
+	}
+	return "unknown"
+}
+
+// observeLatency reports the latency of an attempt with its exact status and, when supported, its status class
+func observeLatency(metrics MetricsRecorder, status int, d time.Duration) {
+	metrics.ObserveLatency(status, d)
+	if recorder, ok := metrics.(StatusClassRecorder); ok {
+		recorder.ObserveLatencyByClass(StatusClass(status), d)
+	}
+}
+
+// noopMetricsRecorder is the default MetricsRecorder that discards everything
+type noopMetricsRecorder struct{}
+
+func (noopMetricsRecorder) IncAttempt()                       {}
+func (noopMetricsRecorder) IncRetry()                         {}
+func (noopMetricsRecorder) ObserveLatency(int, time.Duration) {}
@@ -0,0 +1,27 @@
+package models
+
+import "net/http"
+
+// Middleware wraps a http.RoundTripper to add behavior around every request, such as logging or tracing
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to the http.RoundTripper interface
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// chainMiddlewares wraps transport so the first middleware is the outermost one
+func chainMiddlewares(transport http.RoundTripper, middlewares []Middleware) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+
+	return transport
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	ModelSpecsEndpoint string = "/ml/v1/foundation_model_specs"
+
+	// DefaultModelSpecCacheTTL is how long a spec fetched by GetModelSpec is reused
+	DefaultModelSpecCacheTTL = time.Hour
+)
+
+// ModelSpec describes a foundation model available in the region
+type ModelSpec struct {
+	ModelID          string                `json:"model_id"`
+	Label            string                `json:"label"`
+	Provider         string                `json:"provider"`
+	ShortDescription string                `json:"short_description"`
+	Functions        []ModelFunction       `json:"functions,omitempty"`
+	Limits           ModelLimits           `json:"model_limits"`
+	Lifecycle        []ModelLifecycleStage `json:"lifecycle,omitempty"`
+}
+
+// ModelFunction is a capability of a model, such as "text_generation" or "text_chat"
+type ModelFunction struct {
+	ID string `json:"id"`
+}
+
+// ModelLimits holds the token limits of a model
+type ModelLimits struct {
+	MaxSequenceLength int `json:"max_sequence_length"`
+	MaxOutputTokens   int `json:"max_output_tokens,omitempty"`
+}
+
+// ModelLifecycleStage is a stage of the lifecycle of a model, such as "available", "deprecated" or "withdrawn"
+type ModelLifecycleStage struct {
+	ID        string `json:"id"`
+	StartDate string `json:"start_date,omitempty"`
+}
+
+type modelSpecsPage struct {
+	Resources []ModelSpec `json:"resources"`
+}
+
+// modelSpecEntry is a cached spec and its expiration
+type modelSpecEntry struct {
+	spec    ModelSpec
+	expires time.Time
+}
+
+// modelSpecCache holds the specs fetched by GetModelSpec; it is safe for concurrent use
+type modelSpecCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]modelSpecEntry
+}
+
+func newModelSpecCache(ttl time.Duration) *modelSpecCache {
+	return &modelSpecCache{
+		ttl:     ttl,
+		entries: map[string]modelSpecEntry{},
+	}
+}
+
+func (c *modelSpecCache) get(modelID string, now time.Time) (ModelSpec, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[modelID]
+	if !ok || !now.Before(entry.expires) {
+		return ModelSpec{}, false
+	}
+	return entry.spec, true
+}
+
+func (c *modelSpecCache) set(spec ModelSpec, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[spec.ModelID] = modelSpecEntry{spec: spec, expires: now.Add(c.ttl)}
+}
+
+func (c *modelSpecCache) invalidate(modelID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if modelID == "" {
+		c.entries = map[string]modelSpecEntry{}
+		return
+	}
+	delete(c.entries, modelID)
+}
+
+// GetModelSpec returns the spec of a foundation model, such as its context window and lifecycle.
+// Specs are cached for the TTL set with WithModelSpecCacheTTL, so repeated lookups do not reach the API.
+func (m *Client) GetModelSpec(ctx context.Context, modelID string) (*ModelSpec, error) {
+	if modelID == "" {
+		return nil, fmt.Errorf("model ID cannot be empty")
+	}
+
+	if spec, ok := m.modelSpecs.get(modelID, m.clock.Now()); ok {
+		return &spec, nil
+	}
+
+	m.CheckAndRefreshToken()
+
+	query := url.Values{"filters": {"modelid_" + modelID}}
+	httpReq, err := m.newRequest(ctx, http.MethodGet, m.generateUrlWithQuery(ModelSpecsEndpoint, query), "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := m.httpClient.DoWithRetry(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+
+	var page modelSpecsPage
+	if err := decodeJSONStream(m.codec, res, &page); err != nil {
+		return nil, err
+	}
+
+	for _, spec := range page.Resources {
+		if spec.ModelID == modelID {
+			m.modelSpecs.set(spec, m.clock.Now())
+			return &spec, nil
+		}
+	}
+
+	return nil, fmt.Errorf("model %q not found", modelID)
+}
+
+// InvalidateModelSpec drops the cached spec of a model, or every cached spec when modelID is empty
+func (m *Client) InvalidateModelSpec(modelID string) {
+	m.modelSpecs.invalidate(modelID)
+}
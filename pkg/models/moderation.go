@@ -0,0 +1,93 @@
+package models
+
+import "encoding/json"
+
+// Moderations configures the HAP (hate, abuse, profanity) and PII moderations of a generation
+type Moderations struct {
+	HAP *ModerationOptions `json:"hap,omitempty"`
+	PII *ModerationOptions `json:"pii,omitempty"`
+}
+
+// ModerationOptions configures a moderation on the input and output text
+type ModerationOptions struct {
+	Input  *ModerationTextOptions `json:"input,omitempty"`
+	Output *ModerationTextOptions `json:"output,omitempty"`
+	Mask   *ModerationMask        `json:"mask,omitempty"`
+}
+
+// ModerationTextOptions enables a moderation and sets its detection threshold (0 to 1)
+type ModerationTextOptions struct {
+	Enabled   bool     `json:"enabled"`
+	Threshold *float64 `json:"threshold,omitempty"`
+}
+
+// ModerationMask configures how detected entities are masked
+type ModerationMask struct {
+	RemoveEntityValue bool `json:"remove_entity_value"`
+}
+
+// ModerationType is the moderation that produced a detection
+type ModerationType string
+
+const (
+	ModerationTypeHAP ModerationType = "hap" // Hate, abuse and profanity
+	ModerationTypePII ModerationType = "pii" // Personally identifiable information
+)
+
+// ModerationResults holds the detections returned alongside a generated text
+type ModerationResults struct {
+	HAP []ModerationResult `json:"hap,omitempty"`
+	PII []ModerationResult `json:"pii,omitempty"`
+}
+
+// UnmarshalJSON decodes the detections and sets their Type from the list they are returned in
+func (r *ModerationResults) UnmarshalJSON(data []byte) error {
+	type moderationResults ModerationResults // drops the method to avoid recursion
+	var decoded moderationResults
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	for i := range decoded.HAP {
+		decoded.HAP[i].Type = ModerationTypeHAP
+	}
+	for i := range decoded.PII {
+		decoded.PII[i].Type = ModerationTypePII
+	}
+
+	*r = ModerationResults(decoded)
+	return nil
+}
+
+// All returns the HAP then PII detections, such as to redact every detected range of a text
+func (r *ModerationResults) All() []ModerationResult {
+	if r == nil {
+		return nil
+	}
+	all := make([]ModerationResult, 0, len(r.HAP)+len(r.PII))
+	all = append(all, r.HAP...)
+	return append(all, r.PII...)
+}
+
+// ModerationResult is a single moderation detection
+type ModerationResult struct {
+	Type     ModerationType     `json:"-"`
+	Score    float64            `json:"score"`
+	Input    bool               `json:"input"` // true if detected in the input, false if in the generated text
+	Position ModerationPosition `json:"position"`
+	Entity   string             `json:"entity"`
+}
+
+// ModerationPosition is the character range of a detection
+type ModerationPosition struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// newModerationOptions enables a moderation on both input and output with the given threshold
+func newModerationOptions(threshold float64) *ModerationOptions {
+	return &ModerationOptions{
+		Input:  &ModerationTextOptions{Enabled: true, Threshold: &threshold},
+		Output: &ModerationTextOptions{Enabled: true, Threshold: &threshold},
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrRepeatedCursor is returned when a page names its own cursor as the next one, which would never end
+var ErrRepeatedCursor = errors.New("next cursor repeats the current cursor")
+
+// PageFetchFunc fetches the page identified by cursor and returns its items and the next cursor.
+// An empty next cursor means there are no more pages.
+type PageFetchFunc[T any] func(ctx context.Context, cursor string) (items []T, nextCursor string, err error)
+
+// Paginator iterates over the pages of a cursor-paginated list endpoint
+type Paginator[T any] struct {
+	fetch  PageFetchFunc[T]
+	cursor string
+	done   bool
+}
+
+// NewPaginator creates a paginator that starts from the first page
+func NewPaginator[T any](fetch PageFetchFunc[T]) *Paginator[T] {
+	return &Paginator[T]{
+		fetch: fetch,
+	}
+}
+
+// HasNext reports whether there are more pages to fetch
+func (p *Paginator[T]) HasNext() bool {
+	return !p.done
+}
+
+// Next fetches the next page; it returns nil items once every page has been fetched
+func (p *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items, nextCursor, err := p.fetch(ctx, p.cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if nextCursor != "" && nextCursor == p.cursor {
+		p.done = true
+		return nil, fmt.Errorf("%w: %q", ErrRepeatedCursor, nextCursor)
+	}
+
+	p.cursor = nextCursor
+	p.done = nextCursor == ""
+
+	return items, nil
+}
+
+// All fetches every remaining page and returns the items in order
+func (p *Paginator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for p.HasNext() {
+		items, err := p.Next(ctx)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// PollFunc returns the current status of an asynchronous job
+type PollFunc func() (status string, err error)
+
+// PollUntil calls fetch until it returns one of the terminal statuses, which is then returned.
+// Polls are spaced by interval plus a random jitter of up to half the interval.
+// It stops early with the error of fetch or of the context.
+func PollUntil(ctx context.Context, fetch PollFunc, terminal []string, interval time.Duration) (string, error) {
+	return poll(ctx, fetch, terminal, timerImpl{}, func(n uint) time.Duration {
+		if interval <= 1 {
+			return interval
+		}
+		return interval + time.Duration(rand.Int63n(int64(interval/2)+1))
+	})
+}
+
+// poll calls fetch until it returns a terminal status, waiting delay(n) after the poll n (0-based)
+func poll(ctx context.Context, fetch PollFunc, terminal []string, timer Timer, delay func(n uint) time.Duration) (string, error) {
+	for n := uint(0); ; n++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		status, err := fetch()
+		if err != nil {
+			return status, err
+		}
+
+		for _, t := range terminal {
+			if status == t {
+				return status, nil
+			}
+		}
+
+		select {
+		case <-timer.After(clampToDeadline(ctx, delay(n))):
+		case <-ctx.Done():
+			return status, ctx.Err()
+		}
+	}
+}
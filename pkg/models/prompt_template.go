@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMissingPromptVariable is returned when a template placeholder has no matching variable
+var ErrMissingPromptVariable = errors.New("missing prompt variable")
+
+// PromptTemplate is a prompt with {{var}} placeholders.
+// A placeholder can be escaped with a backslash (\{{) to render literal braces.
+type PromptTemplate struct {
+	template string
+}
+
+// NewPromptTemplate creates a prompt template from the given template string
+func NewPromptTemplate(template string) *PromptTemplate {
+	return &PromptTemplate{
+		template: template,
+	}
+}
+
+// Render substitutes every {{var}} placeholder with its value from vars.
+// Returns an error listing the missing variables if any placeholder has no value.
+func (p *PromptTemplate) Render(vars map[string]string) (string, error) {
+	var sb strings.Builder
+	var missing []string
+
+	s := p.template
+	for {
+		start := strings.Index(s, "{{")
+		if start < 0 {
+			sb.WriteString(s)
+			break
+		}
+
+		// Escaped placeholder, keep the braces as-is
+		if start > 0 && s[start-1] == '\\' {
+			sb.WriteString(s[:start-1])
+			sb.WriteString("{{")
+			s = s[start+2:]
+			continue
+		}
+
+		sb.WriteString(s[:start])
+
+		end := strings.Index(s[start+2:], "}}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in prompt template: %q", s[start:])
+		}
+
+		name := strings.TrimSpace(s[start+2 : start+2+end])
+		if value, ok := vars[name]; ok {
+			sb.WriteString(value)
+		} else {
+			missing = append(missing, name)
+		}
+
+		s = s[start+2+end+2:]
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingPromptVariable, strings.Join(missing, ", "))
+	}
+
+	return sb.String(), nil
+}
+
+// String returns the raw template
+func (p *PromptTemplate) String() string {
+	return p.template
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// resetEpochThreshold separates X-RateLimit-Reset values given as Unix timestamps from values given in seconds
+const resetEpochThreshold = 1_000_000_000
+
+// RateLimitInfo holds the rate limit state reported by watsonx on a response,
+// so callers can slow down before being throttled with 429 responses.
+type RateLimitInfo struct {
+	Limit     int       // Requests allowed in the current window, from X-RateLimit-Limit
+	Remaining int       // Requests left in the current window, from X-RateLimit-Remaining
+	Reset     time.Time // When the window resets, from X-RateLimit-Reset; zero if not sent
+}
+
+// ParseRateLimitInfo reads the X-RateLimit-* headers of a response.
+// It returns nil when none of them is set; headers that cannot be parsed are left at zero.
+// X-RateLimit-Reset is accepted both as a number of seconds and as a Unix timestamp.
+func ParseRateLimitInfo(header http.Header) *RateLimitInfo {
+	limit := header.Get("X-RateLimit-Limit")
+	remaining := header.Get("X-RateLimit-Remaining")
+	reset := header.Get("X-RateLimit-Reset")
+	if limit == "" && remaining == "" && reset == "" {
+		return nil
+	}
+
+	info := &RateLimitInfo{}
+	info.Limit, _ = strconv.Atoi(limit)
+	info.Remaining, _ = strconv.Atoi(remaining)
+
+	if seconds, err := strconv.ParseInt(reset, 10, 64); err == nil && seconds >= 0 {
+		if seconds >= resetEpochThreshold {
+			info.Reset = time.Unix(seconds, 0)
+		} else {
+			info.Reset = time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+	}
+
+	return info
+}
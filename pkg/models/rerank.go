@@ -0,0 +1,120 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+const (
+	RerankEndpoint string = "/ml/v1/text/rerank"
+)
+
+// RerankRequest describes a query and the documents to rank against it
+type RerankRequest struct {
+	Model     string
+	Query     string
+	Documents []string
+
+	TopN                *uint // only return the TopN highest scoring documents
+	TruncateInputTokens *uint
+}
+
+type RerankInput struct {
+	Text string `json:"text"`
+}
+
+type RerankReturnOptions struct {
+	TopN   *uint `json:"top_n,omitempty"`
+	Inputs bool  `json:"inputs"`
+}
+
+type RerankParameters struct {
+	TruncateInputTokens *uint                `json:"truncate_input_tokens,omitempty"`
+	ReturnOptions       *RerankReturnOptions `json:"return_options,omitempty"`
+}
+
+type RerankPayload struct {
+	ProjectID  string            `json:"project_id,omitempty"`
+	SpaceID    string            `json:"space_id,omitempty"`
+	Model      string            `json:"model_id"`
+	Query      string            `json:"query"`
+	Inputs     []RerankInput     `json:"inputs"`
+	Parameters *RerankParameters `json:"parameters,omitempty"`
+}
+
+// RerankResponse holds the documents sorted by descending relevance score
+type RerankResponse struct {
+	Model           string         `json:"model_id"`
+	Results         []RerankResult `json:"results"`
+	CreatedAt       time.Time      `json:"created_at"`
+	InputTokenCount int64          `json:"input_token_count"`
+}
+
+// RerankResult holds the relevance score of a document.
+// Index is the position of the document in RerankRequest.Documents.
+type RerankResult struct {
+	Index int          `json:"index"`
+	Score float64      `json:"score"`
+	Input *RerankInput `json:"input,omitempty"`
+}
+
+// Rerank scores the documents by relevance to the query
+func (m *Client) Rerank(ctx context.Context, req RerankRequest) (*RerankResponse, error) {
+	m.CheckAndRefreshToken()
+
+	if req.Query == "" {
+		return nil, errors.New("query cannot be empty")
+	}
+
+	if len(req.Documents) == 0 {
+		return nil, errors.New("documents cannot be empty")
+	}
+
+	inputs := make([]RerankInput, len(req.Documents))
+	for i, document := range req.Documents {
+		inputs[i] = RerankInput{Text: document}
+	}
+
+	payload := RerankPayload{
+		ProjectID: m.projectID,
+		SpaceID:   m.spaceID,
+		Model:     req.Model,
+		Query:     req.Query,
+		Inputs:    inputs,
+		Parameters: &RerankParameters{
+			TruncateInputTokens: req.TruncateInputTokens,
+			ReturnOptions: &RerankReturnOptions{
+				TopN:   req.TopN,
+				Inputs: true,
+			},
+		},
+	}
+
+	httpReq, err := m.newJSONRequest(ctx, RerankEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := m.httpClient.DoWithRetry(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+
+	var rerankRes RerankResponse
+	if err := decodeJSONStream(m.codec, res, &rerankRes); err != nil {
+		return nil, err
+	}
+
+	if len(rerankRes.Results) == 0 {
+		return nil, errors.New("no result received")
+	}
+
+	sort.SliceStable(rerankRes.Results, func(i, j int) bool {
+		return rerankRes.Results[i].Score > rerankRes.Results[j].Score
+	})
+
+	return &rerankRes, nil
+}
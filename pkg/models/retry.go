@@ -3,9 +3,21 @@ package models
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"log"
+	"math"
 	"math/rand"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,15 +32,46 @@ type Timer interface {
 // RetryIfFunc determines whether a retry should be attempted based on the error.
 type RetryIfFunc func(error) bool
 
+// BackoffFunc computes the delay before a retry from its attempt number (1-based) and the error of the failed attempt.
+type BackoffFunc func(attempt uint, err error) time.Duration
+
+// backoffStrategy determines how the backoff grows between retries.
+type backoffStrategy int
+
+const (
+	fixedBackoff       backoffStrategy = iota // same backoff for every retry
+	exponentialBackoff                        // backoff doubles on every retry
+	linearBackoff                             // backoff grows by the same step on every retry
+)
+
 // RetryConfig contains configuration options for the retry mechanism.
 type RetryConfig struct {
-	retries   uint
-	backoff   time.Duration
-	maxJitter time.Duration
-	onRetry   OnRetryFunc
-	retryIf   RetryIfFunc
-	timer     Timer
-	context   context.Context
+	retries      uint
+	backoff      time.Duration
+	strategy     backoffStrategy
+	maxBackoff   time.Duration
+	minBackoff   time.Duration
+	maxJitter    time.Duration
+	jitterFactor float64
+	randInt63n   func(n int64) int64
+	backoffFunc  BackoffFunc
+	onRetry      OnRetryFunc
+	retryIf      RetryIfFunc
+	timer        Timer
+	clock        Clock
+	context      context.Context
+	metrics      MetricsRecorder
+
+	maxErrorBodySize int64
+	retryBudget      *RetryBudget
+	maxElapsedTime   time.Duration
+	attemptTimeout   time.Duration
+
+	retryableStatusCodes map[int]bool
+	retryErrorCodes      map[string]bool
+	responseValidator    ResponseValidatorFunc
+	retryIncompleteJSON  bool
+	rawResponses         bool
 }
 
 // RetryOption is a function type for modifying RetryConfig options.
@@ -44,13 +87,18 @@ func (t timerImpl) After(d time.Duration) <-chan time.Time {
 // newDefaultRetryConfig creates a default RetryConfig with sensible defaults.
 func newDefaultRetryConfig() *RetryConfig {
 	return &RetryConfig{
-		retries:   3,
-		backoff:   1 * time.Second,
-		maxJitter: 1 * time.Second,
-		onRetry:   func(n uint, err error) {},                 // no-op onRetry by default
-		retryIf:   func(err error) bool { return err != nil }, // retry on any error by default
-		timer:     &timerImpl{},
-		context:   context.Background(),
+		retries:    3,
+		backoff:    1 * time.Second,
+		maxJitter:  1 * time.Second,
+		randInt63n: rand.Int63n,
+		onRetry:    func(n uint, err error) {}, // no-op onRetry by default
+		retryIf:    retryOnRetryableClass,      // retry transient, rate limited, server and network errors by default
+		timer:      &timerImpl{},
+		clock:      realClock{},
+		context:    context.Background(),
+		metrics:    noopMetricsRecorder{},
+
+		maxErrorBodySize: DefaultMaxErrorBodySize,
 	}
 }
 
@@ -58,7 +106,18 @@ func newDefaultRetryConfig() *RetryConfig {
 type RetryableFuncWithResponse func() (*http.Response, error)
 
 // Retry retries the provided retryableFunc according to the retry configuration options.
+// By default only the errors whose ClassifyError class is Retryable are retried; other errors, including
+// plain errors returned by retryableFunc, end the loop at once, where earlier versions retried every error.
+// Use WithRetryIf to change the condition.
 func Retry(retryableFunc RetryableFuncWithResponse, options ...RetryOption) (*http.Response, error) {
+	return retryWithAttemptContext(func(context.Context) (*http.Response, error) {
+		return retryableFunc()
+	}, options...)
+}
+
+// retryWithAttemptContext is Retry for functions that take the context of the attempt,
+// which is cancelled when the attempt timeout elapses.
+func retryWithAttemptContext(retryableFunc attemptFunc, options ...RetryOption) (*http.Response, error) {
 	opts := newDefaultRetryConfig()
 
 	for _, opt := range options {
@@ -67,55 +126,384 @@ func Retry(retryableFunc RetryableFuncWithResponse, options ...RetryOption) (*ht
 		}
 	}
 
+	started := opts.clock.Now()
+
 	var lastErr error
+	var rawResp *http.Response // response of the last failed attempt, kept with WithRawResponses
 	for n := uint(0); n < opts.retries; n++ {
 		if err := opts.context.Err(); err != nil {
-			return nil, err
+			closeResponse(rawResp)
+			return nil, newRetryContextError(err, n, lastErr)
 		}
 
-		resp, err := retryableFunc()
-		if err == nil && resp != nil && resp.StatusCode == http.StatusOK {
-			return resp, nil
+		opts.metrics.IncAttempt()
+		start := opts.clock.Now()
+		resp, err := opts.attempt(retryableFunc)
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
 		}
+		observeLatency(opts.metrics, status, opts.clock.Now().Sub(start))
 
-		// Convert non-200 HTTP responses into detailed errors
+		// Jobs such as text extractions answer 201 Created, so any 2xx is a success
+		if err == nil && resp != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			validator := opts.validator()
+			if validator == nil {
+				return resp, nil
+			}
+			if err = validateResponse(resp, validator); err == nil {
+				return resp, nil
+			}
+		}
+
+		// A request that could not be built would fail the same way again, whatever retryIf decides
+		var requestErr *RequestError
+		if errors.As(err, &requestErr) {
+			closeResponse(rawResp)
+			return nil, err
+		}
+
+		// Convert non-2xx HTTP responses into detailed errors
+		transient := false
 		if err == nil && resp != nil {
-			// Read and preserve the response body
-			bodyBytes, readErr := io.ReadAll(resp.Body)
-			resp.Body.Close()
+			// Read and preserve the response body, up to the configured limit
+			body := resp.Body
+			reader := io.Reader(body)
+			var consumed bytes.Buffer
+			if opts.rawResponses {
+				// Record every byte read, including the one past the limit that detects oversized bodies
+				reader = io.TeeReader(body, &consumed)
+			}
+
+			bodyBytes, truncated, readErr := readErrorBody(reader, opts.maxErrorBodySize)
+			keepBody := opts.rawResponses && truncated && readErr == nil
+			if truncated && !keepBody {
+				// Closing the connection is cheaper than reading the rest of an oversized body
+				body.Close()
+			} else if !truncated {
+				drainAndClose(body)
+			}
 
 			if readErr != nil {
-				err = readErr
+				err = fmt.Errorf("attempt %d: reading error response body: %w", n+1, readErr)
+				transient = errors.Is(readErr, io.ErrUnexpectedEOF)
 			} else {
-				// Restore body so DecodeWatsonxError can read it
+				// Restore body so it can be read again
 				resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+				if keepBody {
+					// Raw responses keep the unread rest of an oversized body
+					resp.Body = readCloser{io.MultiReader(&consumed, body), body}
+				}
 
 				// Parse detailed WatsonX error
-				err = DecodeWatsonxError(resp)
+				err = newWatsonxError(resp, bodyBytes, truncated, codecFromContext(opts.context))
 			}
 		}
 
-		if !opts.retryIf(err) {
-			return nil, err
+		closeResponse(rawResp)
+		rawResp = nil
+		if opts.rawResponses && err != nil && resp != nil && !transient {
+			rawResp = resp
+		}
+
+		// A connection dropped mid-response or an opted-in error code is always retried, whatever the status and retryIf decide
+		forceRetry := transient || opts.hasRetryErrorCode(err)
+
+		// Only the opted-in statuses are retried when they are set, including 4xx that retryIf would reject
+		if opts.retryableStatusCodes != nil && resp != nil && !forceRetry {
+			if !opts.retryableStatusCodes[resp.StatusCode] {
+				return opts.failed(rawResp, err, n+1)
+			}
+			forceRetry = true
+		}
+
+		if !forceRetry && !opts.retryIf(err) {
+			return opts.failed(rawResp, err, n+1)
 		}
 
 		lastErr = err
-		opts.onRetry(n+1, err)
 
-		backoffDuration := opts.backoff
-		if opts.maxJitter > 0 {
-			jitter := time.Duration(rand.Int63n(int64(opts.maxJitter)))
-			backoffDuration += jitter
+		delay := opts.delay(n, err)
+		backoffDuration := clampToDeadline(opts.context, delay)
+
+		// Stop when the next attempt would start after the max elapsed time
+		if n+1 < opts.retries && opts.maxElapsedTime > 0 &&
+			opts.clock.Now().Sub(started)+delay > opts.maxElapsedTime {
+			closeResponse(rawResp)
+			return nil, &RetryTimeoutError{Attempts: n + 1, LastErr: withAttemptCount(err, n+1), Err: ErrMaxElapsedTime}
+		}
+
+		// Stop early when the shared retry budget is exhausted
+		if n+1 < opts.retries && opts.retryBudget != nil && !opts.retryBudget.allow() {
+			return opts.failed(rawResp, err, n+1)
 		}
 
+		if n+1 < opts.retries {
+			opts.metrics.IncRetry()
+		}
+		opts.onRetry(n+1, err)
+
 		select {
 		case <-opts.timer.After(backoffDuration):
 		case <-opts.context.Done():
-			return nil, opts.context.Err()
+			closeResponse(rawResp)
+			return nil, newRetryContextError(opts.context.Err(), n+1, lastErr)
+		}
+	}
+
+	return opts.failed(rawResp, lastErr, opts.retries)
+}
+
+// failed returns the error of the final failed attempt,
+// or its response when raw responses are requested and the attempt got one.
+func (cfg *RetryConfig) failed(rawResp *http.Response, err error, attempts uint) (*http.Response, error) {
+	if rawResp != nil {
+		return rawResp, nil
+	}
+	return nil, withAttemptCount(err, attempts)
+}
+
+// hasRetryErrorCode reports whether err is a WatsonxError carrying one of the codes set with WithRetryOnErrorCodes
+func (cfg *RetryConfig) hasRetryErrorCode(err error) bool {
+	if len(cfg.retryErrorCodes) == 0 {
+		return false
+	}
+
+	var apiErr *WatsonxError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, detail := range apiErr.Errors {
+		if cfg.retryErrorCodes[detail.Code] {
+			return true
+		}
+	}
+	return false
+}
+
+// readCloser combines a reader with the closer of the body it reads from
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// closeResponse closes the body of a response that is not returned to the caller
+func closeResponse(resp *http.Response) {
+	if resp != nil {
+		resp.Body.Close()
+	}
+}
+
+// withAttemptCount records the number of attempts made on the WatsonxError of err, if any
+func withAttemptCount(err error, attempts uint) error {
+	var wxErr *WatsonxError
+	if errors.As(err, &wxErr) {
+		wxErr.AttemptCount = attempts
+	}
+	return err
+}
+
+// ResponseValidatorFunc checks a successful response, returning an error if it is not usable
+type ResponseValidatorFunc func(resp *http.Response) error
+
+// InvalidResponseError is returned when a response validator rejects a successful response
+type InvalidResponseError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *InvalidResponseError) Error() string {
+	return fmt.Sprintf("invalid response (%d): %v", e.StatusCode, e.Err)
+}
+
+func (e *InvalidResponseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrIncompleteJSON is the error of an *InvalidResponseError for a successful response whose JSON body
+// is cut short, such as when the connection dropped mid-body. It is only checked with WithRetryOnIncompleteJSON.
+var ErrIncompleteJSON = errors.New("incomplete JSON response body")
+
+// validator returns the validator run on successful responses, or nil if there is none
+func (cfg *RetryConfig) validator() ResponseValidatorFunc {
+	if !cfg.retryIncompleteJSON {
+		return cfg.responseValidator
+	}
+
+	return func(resp *http.Response) error {
+		if isJSONResponse(resp) && resp.StatusCode != http.StatusNoContent {
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			if !json.Valid(body) {
+				return ErrIncompleteJSON
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
 		}
+
+		if cfg.responseValidator == nil {
+			return nil
+		}
+		return cfg.responseValidator(resp)
 	}
+}
 
-	return nil, lastErr
+// isJSONResponse reports whether the response declares a JSON body, such as application/json or application/problem+json
+func isJSONResponse(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// validateResponse buffers the response body and runs the validator on it.
+// The body is restored afterwards so it can still be read by the caller.
+func validateResponse(resp *http.Response, validator ResponseValidatorFunc) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err := validator(resp); err != nil {
+		return &InvalidResponseError{StatusCode: resp.StatusCode, Err: err}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return nil
+}
+
+// ErrMaxElapsedTime is the error of a RetryTimeoutError returned when the max elapsed time stops the retry loop
+var ErrMaxElapsedTime = errors.New("max elapsed time reached")
+
+// RetryTimeoutError is returned when the context deadline or the max elapsed time stops the retry loop.
+// It matches context.DeadlineExceeded or ErrMaxElapsedTime with errors.Is.
+type RetryTimeoutError struct {
+	Attempts uint  // Number of attempts made before the deadline
+	LastErr  error // Error of the last attempt, if any
+	Err      error // The context error, or ErrMaxElapsedTime
+}
+
+func (e *RetryTimeoutError) Error() string {
+	if e.LastErr != nil {
+		return fmt.Sprintf("retry timed out after %d attempts: %v (last error: %v)", e.Attempts, e.Err, e.LastErr)
+	}
+	return fmt.Sprintf("retry timed out after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// newRetryContextError wraps a deadline in a RetryTimeoutError; cancellations are returned as is
+func newRetryContextError(err error, attempts uint, lastErr error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &RetryTimeoutError{Attempts: attempts, LastErr: withAttemptCount(lastErr, attempts), Err: err}
+	}
+	return err
+}
+
+// delay computes the backoff before the retry following attempt n (0-based).
+// A Retry-After sent by the server is used when it is longer than the computed backoff.
+// A BackoffFunc set with WithBackoffFunc replaces the whole computation.
+func (cfg *RetryConfig) delay(n uint, err error) time.Duration {
+	if cfg.backoffFunc != nil {
+		return cfg.backoffFunc(n+1, err)
+	}
+
+	backoff := cfg.backoff
+	switch cfg.strategy {
+	case exponentialBackoff:
+		backoff = exponentialDelay(cfg.backoff, n)
+	case linearBackoff:
+		backoff = linearDelay(cfg.backoff, n)
+	}
+
+	if cfg.maxBackoff > 0 && backoff > cfg.maxBackoff {
+		backoff = cfg.maxBackoff
+	}
+
+	if cfg.maxJitter > 0 {
+		jitter := time.Duration(cfg.randInt63n(int64(cfg.maxJitter)))
+		backoff = saturatingAdd(backoff, jitter)
+	}
+
+	if cfg.jitterFactor > 0 {
+		backoff = cfg.scaleJitter(backoff)
+	}
+
+	if retryAfter := retryAfterFromError(err); retryAfter > backoff {
+		backoff = retryAfter
+	}
+
+	if backoff < cfg.minBackoff {
+		backoff = cfg.minBackoff
+	}
+
+	return backoff
+}
+
+// jitterPrecision is the number of random bits drawn for a proportional jitter
+const jitterPrecision = 1 << 53
+
+// scaleJitter moves the backoff by a random amount within ±jitterFactor of it, saturating instead of overflowing
+func (cfg *RetryConfig) scaleJitter(backoff time.Duration) time.Duration {
+	random := float64(cfg.randInt63n(jitterPrecision)) / jitterPrecision // in [0, 1)
+	jittered := float64(backoff) * (1 + cfg.jitterFactor*(2*random-1))
+	if jittered >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(jittered)
+}
+
+// saturatingAdd returns backoff + jitter, saturating instead of overflowing
+func saturatingAdd(backoff, jitter time.Duration) time.Duration {
+	if backoff > math.MaxInt64-jitter {
+		return math.MaxInt64
+	}
+	return backoff + jitter
+}
+
+// exponentialDelay returns initial * 2^n, saturating instead of overflowing
+func exponentialDelay(initial time.Duration, n uint) time.Duration {
+	if initial <= 0 {
+		return 0
+	}
+	if n >= 62 || initial > math.MaxInt64>>n {
+		return math.MaxInt64
+	}
+	return initial << n
+}
+
+// linearDelay returns step * (n+1), saturating instead of overflowing
+func linearDelay(step time.Duration, n uint) time.Duration {
+	if step <= 0 {
+		return 0
+	}
+	if uint64(n)+1 > uint64(math.MaxInt64/step) {
+		return math.MaxInt64
+	}
+	return step * time.Duration(n+1)
+}
+
+// clampToDeadline shortens the backoff so it does not sleep past the context deadline
+func clampToDeadline(ctx context.Context, backoff time.Duration) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return backoff
+	}
+
+	if remaining := time.Until(deadline); remaining < backoff {
+		if remaining < 0 {
+			return 0
+		}
+		return remaining
+	}
+
+	return backoff
 }
 
 // WithRetries sets the number of retries for the retry configuration.
@@ -125,17 +513,99 @@ func WithRetries(retries uint) RetryOption {
 	}
 }
 
-// WithBackoff sets the backoff duration between retries.
+// WithBackoff sets a constant backoff duration between retries.
 func WithBackoff(backoff time.Duration) RetryOption {
 	return func(cfg *RetryConfig) {
 		cfg.backoff = backoff
+		cfg.strategy = fixedBackoff
+	}
+}
+
+// WithExponentialBackoff doubles the backoff on every retry, starting from initial.
+func WithExponentialBackoff(initial time.Duration) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.backoff = initial
+		cfg.strategy = exponentialBackoff
+	}
+}
+
+// WithLinearBackoff grows the backoff by step on every retry: step, 2*step, 3*step...
+func WithLinearBackoff(step time.Duration) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.backoff = step
+		cfg.strategy = linearBackoff
+	}
+}
+
+// WithBackoffFunc computes every delay between attempts with backoffFunc, bypassing the backoff strategy,
+// the jitter, the min and max backoff and the Retry-After of the server, which is still available on the *WatsonxError.
+func WithBackoffFunc(backoffFunc BackoffFunc) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.backoffFunc = backoffFunc
+	}
+}
+
+// WithMaxBackoff caps the computed backoff before jitter is added.
+// A longer Retry-After sent by the server is still honored.
+func WithMaxBackoff(maxBackoff time.Duration) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.maxBackoff = maxBackoff
+	}
+}
+
+// WithMinBackoff sets a floor on the delay between attempts, applied after the jitter and the Retry-After
+// of the server, so a tiny computed delay never hammers the server. It takes precedence over WithMaxBackoff.
+func WithMinBackoff(minBackoff time.Duration) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.minBackoff = minBackoff
 	}
 }
 
 // WithMaxJitter sets the maximum jitter duration to add to the backoff.
+// A negative duration is treated as zero, which disables the jitter.
+// It replaces the proportional jitter of WithJitterFactor: whichever of the two options is passed last wins.
 func WithMaxJitter(maxJitter time.Duration) RetryOption {
 	return func(cfg *RetryConfig) {
+		if maxJitter < 0 {
+			maxJitter = 0
+		}
 		cfg.maxJitter = maxJitter
+		cfg.jitterFactor = 0
+	}
+}
+
+// WithNoJitter disables the jitter so every retry waits exactly the computed backoff.
+// Jitter is enabled by default (up to 1 second) to spread out retries from concurrent clients.
+func WithNoJitter() RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.maxJitter = 0
+		cfg.jitterFactor = 0
+	}
+}
+
+// WithJitterFactor randomizes each backoff by up to ±factor of it, e.g. 0.2 for ±20%, so the jitter
+// grows with exponential backoff. The factor is clamped to [0, 1].
+// It replaces the absolute jitter of WithMaxJitter: whichever of the two options is passed last wins.
+func WithJitterFactor(factor float64) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.jitterFactor = min(max(factor, 0), 1)
+		cfg.maxJitter = 0
+	}
+}
+
+// WithRandSource sets the random source used to compute the jitter, e.g. a seeded one for reproducible tests.
+// Access to the source is serialized, so it can be shared by concurrent retries.
+func WithRandSource(r *rand.Rand) RetryOption {
+	return func(cfg *RetryConfig) {
+		if r == nil {
+			return
+		}
+		var mu sync.Mutex
+		cfg.randInt63n = func(n int64) int64 {
+			mu.Lock()
+			defer mu.Unlock()
+			return r.Int63n(n)
+		}
 	}
 }
 
@@ -147,60 +617,406 @@ func WithOnRetry(onRetry OnRetryFunc) RetryOption {
 }
 
 // WithRetryIf sets the condition to determine whether to retry based on the error.
+// It replaces the default condition, which retries the transient, rate limited, server and network errors
+// of ClassifyError, so func(err error) bool { return err != nil } retries every error as earlier versions did.
 func WithRetryIf(retryIf RetryIfFunc) RetryOption {
 	return func(cfg *RetryConfig) {
 		cfg.retryIf = retryIf
 	}
 }
 
+// WithTimer sets the Timer used to wait between retries.
+func WithTimer(timer Timer) RetryOption {
+	return func(cfg *RetryConfig) {
+		if timer != nil {
+			cfg.timer = timer
+		}
+	}
+}
+
+// WithClock sets the Clock used to measure the elapsed time of the retry loop.
+func WithClock(clock Clock) RetryOption {
+	return func(cfg *RetryConfig) {
+		if clock != nil {
+			cfg.clock = clock
+		}
+	}
+}
+
+// WithMaxElapsedTime stops retrying when the next attempt would start more than maxElapsedTime
+// after the first one, returning a *RetryTimeoutError that matches ErrMaxElapsedTime.
+// Zero, the default, sets no limit.
+func WithMaxElapsedTime(maxElapsedTime time.Duration) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.maxElapsedTime = maxElapsedTime
+	}
+}
+
+// WithAttemptTimeout cancels an attempt that has not received its response headers after timeout,
+// and retries it like a network error, while the context of the call still bounds all the attempts.
+// The attempt context reaches the requests sent by HttpClient.DoWithRetry; a function passed to
+// Retry does not see it, so only the attempts that return late are counted as timed out.
+// Zero, the default, sets no limit.
+func WithAttemptTimeout(timeout time.Duration) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.attemptTimeout = timeout
+	}
+}
+
+// WithContext sets the context that cancels the retry loop.
+func WithContext(ctx context.Context) RetryOption {
+	return func(cfg *RetryConfig) {
+		if ctx != nil {
+			cfg.context = ctx
+		}
+	}
+}
+
+// WithMetrics sets the recorder that receives attempt, retry and latency metrics.
+func WithMetrics(metrics MetricsRecorder) RetryOption {
+	return func(cfg *RetryConfig) {
+		if metrics != nil {
+			cfg.metrics = metrics
+		}
+	}
+}
+
+// WithMaxErrorBodySize sets the maximum number of bytes read from an error response body.
+// Defaults to DefaultMaxErrorBodySize; a size of zero or less reads the whole body.
+func WithMaxErrorBodySize(size int64) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.maxErrorBodySize = size
+	}
+}
+
+// WithRetryableStatusCodes retries only responses with one of the given status codes, such as 429 and 503.
+// Other statuses are returned immediately as a WatsonxError; errors without a response are still retried.
+func WithRetryableStatusCodes(statusCodes ...int) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.retryableStatusCodes = map[int]bool{}
+		for _, statusCode := range statusCodes {
+			cfg.retryableStatusCodes[statusCode] = true
+		}
+	}
+}
+
+// WithRetryOnErrorCodes retries responses whose decoded WatsonxError carries one of the given codes, such as
+// "model_loading", even when their status would not be retried otherwise.
+func WithRetryOnErrorCodes(codes ...string) RetryOption {
+	return func(cfg *RetryConfig) {
+		if cfg.retryErrorCodes == nil {
+			cfg.retryErrorCodes = map[string]bool{}
+		}
+		for _, code := range codes {
+			cfg.retryErrorCodes[code] = true
+		}
+	}
+}
+
+// WithResponseValidator checks every successful response, such as a 200 that carries an error payload.
+// A rejected response fails the attempt with an *InvalidResponseError, which is retried like other errors.
+// The validator can read the body, which is buffered and restored for the caller, so it should not be used for streams.
+func WithResponseValidator(validator ResponseValidatorFunc) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.responseValidator = validator
+	}
+}
+
+// WithRetryOnIncompleteJSON retries successful responses whose JSON body does not parse, such as a body cut short
+// by a dropped connection, since another attempt may succeed. The body is buffered to be checked, so responses
+// declared as JSON should not be streamed; event streams are not checked.
+func WithRetryOnIncompleteJSON() RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.retryIncompleteJSON = true
+	}
+}
+
+// WithRawResponses returns the response of the final failed attempt, with its body intact, and a nil error
+// instead of decoding it into a *WatsonxError. Failed attempts are still retried according to the other options,
+// and the caller must check the status code and close the body.
+func WithRawResponses() RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.rawResponses = true
+	}
+}
+
+// WithRetryBudget limits retries with a budget that can be shared across requests and clients.
+// Pass the same RetryBudget to every client that should draw from the same pool.
+func WithRetryBudget(budget *RetryBudget) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.retryBudget = budget
+	}
+}
+
 // Custom wrapper for http.Client that implements the Doer interface.
 // - Do
 // - DoWithRetry
 type HttpClient struct {
-	httpClient *http.Client
+	httpClient     *http.Client
+	baseTransport  http.RoundTripper // transport below the middlewares, whose idle connections Close releases
+	retryOptions   []RetryOption
+	circuitBreaker *CircuitBreaker
+	idempotencyKey bool
+	retryStats     *RetryStats
+	maxBodySize    int64
+	middlewares    []Middleware
+
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+
+	http2               *bool
+	maxIdleConnsPerHost int
+
+	proxySet bool
+	proxy    func(*http.Request) (*url.URL, error)
+
+	clientCertificates []tls.Certificate
+	rootCAs            *x509.CertPool
+	insecureSkipVerify bool
+	logger             *log.Logger // receives the warnings of the client, the standard logger by default
+
+	hedgeDelay time.Duration
+	maxHedges  int
+
+	limiter concurrencyLimiter // nil when the number of requests in flight is not capped
+
+	interceptors []RequestInterceptorFunc
 }
 
-func NewHttpClient() *HttpClient {
-	return &HttpClient{
+func NewHttpClient(options ...HttpClientOption) *HttpClient {
+	c := &HttpClient{
 		httpClient: &http.Client{},
 	}
+
+	for _, opt := range options {
+		if opt != nil {
+			opt(c)
+		}
+	}
+
+	if c.insecureSkipVerify {
+		logger := c.logger
+		if logger == nil {
+			logger = log.Default()
+		}
+		logger.Println("WARNING: TLS certificate verification is disabled with WithInsecureSkipVerify; " +
+			"connections are open to man-in-the-middle attacks, do not use this in production")
+	}
+
+	c.applyTransportSettings()
+
+	c.baseTransport = c.httpClient.Transport
+	if c.baseTransport == nil {
+		c.baseTransport = http.DefaultTransport
+	}
+
+	if len(c.middlewares) > 0 {
+		c.httpClient.Transport = chainMiddlewares(c.httpClient.Transport, c.middlewares)
+	}
+
+	return c
+}
+
+// applyTransportSettings sets the configured timeouts and connection settings on a copy of the transport.
+// They are ignored when the transport set with WithTransport is not an *http.Transport.
+func (c *HttpClient) applyTransportSettings() {
+	if c.dialTimeout == 0 && c.tlsHandshakeTimeout == 0 && c.responseHeaderTimeout == 0 &&
+		c.http2 == nil && c.maxIdleConnsPerHost == 0 && !c.proxySet &&
+		len(c.clientCertificates) == 0 && c.rootCAs == nil && !c.insecureSkipVerify {
+		return
+	}
+
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return
+	}
+	transport = transport.Clone()
+
+	if c.dialTimeout > 0 {
+		dialer := &net.Dialer{Timeout: c.dialTimeout, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+	}
+	if c.tlsHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = c.tlsHandshakeTimeout
+	}
+	if c.responseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = c.responseHeaderTimeout
+	}
+	if c.http2 != nil {
+		transport.ForceAttemptHTTP2 = *c.http2
+		if !*c.http2 {
+			// A non-nil empty map disables HTTP/2
+			transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		}
+	}
+	if c.maxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = c.maxIdleConnsPerHost
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < c.maxIdleConnsPerHost {
+			transport.MaxIdleConns = c.maxIdleConnsPerHost
+		}
+	}
+	if c.proxySet {
+		transport.Proxy = c.proxy
+	}
+	if len(c.clientCertificates) > 0 || c.rootCAs != nil || c.insecureSkipVerify {
+		tlsConfig := &tls.Config{}
+		if transport.TLSClientConfig != nil {
+			tlsConfig = transport.TLSClientConfig.Clone()
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, c.clientCertificates...)
+		if c.rootCAs != nil {
+			tlsConfig.RootCAs = c.rootCAs
+		}
+		if c.insecureSkipVerify {
+			tlsConfig.InsecureSkipVerify = true
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	c.httpClient.Transport = transport
 }
 
 func (c *HttpClient) Do(req *http.Request) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.acquire(req.Context()); err != nil {
+			return nil, err
+		}
+		defer c.limiter.release()
+	}
+
 	return c.httpClient.Do(req)
 }
 
-func (c *HttpClient) DoWithRetry(req *http.Request) (*http.Response, error) {
+// Close releases the idle connections kept by the underlying transport.
+// The transport wrapped by middlewares is closed directly, since the wrappers do not forward the call.
+func (c *HttpClient) Close() error {
+	if closer, ok := c.baseTransport.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+		return nil
+	}
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+func (c *HttpClient) DoWithRetry(req *http.Request) (resp *http.Response, err error) {
+	// The slot is held across the attempts of the request
+	if c.limiter != nil {
+		if err := c.limiter.acquire(req.Context()); err != nil {
+			return nil, err
+		}
+		defer c.limiter.release()
+	}
+
+	// Generate the idempotency key once so every attempt carries the same value
+	if c.idempotencyKey && req.Header.Get(IdempotencyKeyHeader) == "" {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
 	// Get a reusable body function to allow retries with the same request body
-	getBody, err := getReusableBody(req)
+	getBody, retryable, err := getReusableBody(req, c.maxBodySize)
 	if err != nil {
 		return nil, err
 	}
-	return Retry(
-		func() (*http.Response, error) {
+
+	// The breaker is checked once the request is ready to be sent, so a request failing before
+	// never holds the half-open probe, which is released on every exit below
+	if c.circuitBreaker != nil {
+		if err := c.circuitBreaker.Allow(); err != nil {
+			getBody().Close()
+			return nil, err
+		}
+		defer func() {
+			c.circuitBreaker.record(resp, err)
+		}()
+	}
+
+	retryOptions := append([]RetryOption{WithContext(req.Context())}, c.retryOptions...)
+	if deadline, ok := callDeadlineFromContext(req.Context()); ok {
+		// A per-call deadline overrides the max elapsed time of the client
+		retryOptions = append(retryOptions, WithMaxElapsedTime(max(time.Until(deadline), 1)))
+	}
+	if !retryable {
+		// The body is too large to buffer, so it can only be sent once
+		retryOptions = append(retryOptions, WithRetries(1))
+	}
+
+	attempt := uint(0)
+	resp, err = retryWithAttemptContext(
+		func(ctx context.Context) (*http.Response, error) {
+			attempt++
+			if c.retryStats != nil {
+				c.retryStats.recordAttempt(attempt)
+			}
+
+			attemptReq := req
+			if ctx != req.Context() {
+				attemptReq = req.Clone(ctx)
+			}
+			if len(c.interceptors) > 0 {
+				// Every attempt starts from the request as built, so interceptors do not pile up changes
+				attemptReq = req.Clone(ctx)
+				for _, intercept := range c.interceptors {
+					if intercept == nil {
+						continue
+					}
+					if err := intercept(attemptReq); err != nil {
+						return nil, &RequestError{Op: "intercept request", Err: err}
+					}
+				}
+			}
+
 			// Reset the request body for each retry attempt
-			req.Body = getBody()
-			return c.httpClient.Do(req)
+			return c.doHedged(attemptReq, getBody, retryable)
 		},
+		retryOptions...,
 	)
+
+	if c.retryStats != nil {
+		c.retryStats.recordResult(err)
+	}
+
+	return resp, err
 }
 
 // getReusableBody reads the request body and returns a function that creates a new io.ReadCloser
-// This allows the request body to be reused across multiple retry attempts
-func getReusableBody(req *http.Request) (func() io.ReadCloser, error) {
+// This allows the request body to be reused across multiple retry attempts.
+// When maxSize is positive and the body is larger, it is not buffered: the returned function
+// hands out the original body once and retryable is false.
+func getReusableBody(req *http.Request, maxSize int64) (getBody func() io.ReadCloser, retryable bool, err error) {
 	if req.Body == nil || req.Body == http.NoBody {
-		return func() io.ReadCloser { return http.NoBody }, nil
+		return func() io.ReadCloser { return http.NoBody }, true, nil
 	}
 
-	// Read the entire body
-	bodyBytes, err := io.ReadAll(req.Body)
-	req.Body.Close()
+	reader := io.Reader(req.Body)
+	if maxSize > 0 {
+		// Read one byte past the limit to detect oversized bodies
+		reader = io.LimitReader(req.Body, maxSize+1)
+	}
+
+	bodyBytes, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, err
+		req.Body.Close()
+		return nil, false, err
+	}
+
+	if maxSize > 0 && int64(len(bodyBytes)) > maxSize {
+		body := readCloser{io.MultiReader(bytes.NewReader(bodyBytes), req.Body), req.Body}
+		return func() io.ReadCloser { return body }, false, nil
 	}
+	req.Body.Close()
 
 	// Return a function that creates a new reader from the saved bytes
 	return func() io.ReadCloser {
 		return io.NopCloser(bytes.NewReader(bodyBytes))
-	}, nil
+	}, true, nil
 }
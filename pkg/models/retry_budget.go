@@ -0,0 +1,67 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryBudget is a token bucket shared by requests to limit retries during incidents.
+// Every retry takes a token; when the bucket is empty the last error is returned instead of retrying.
+// First attempts are never limited. It is safe for concurrent use.
+type RetryBudget struct {
+	mu sync.Mutex
+
+	capacity        float64
+	refillPerSecond float64
+
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewRetryBudget creates a full budget of capacity retries, refilled at refillPerSecond tokens per second
+func NewRetryBudget(capacity int, refillPerSecond float64) *RetryBudget {
+	if capacity < 0 {
+		capacity = 0
+	}
+	if refillPerSecond < 0 {
+		refillPerSecond = 0
+	}
+	return &RetryBudget{
+		capacity:        float64(capacity),
+		refillPerSecond: refillPerSecond,
+		tokens:          float64(capacity),
+		lastRefill:      time.Now(),
+	}
+}
+
+// Available returns the number of retries the budget currently allows
+func (b *RetryBudget) Available() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	return int(b.tokens)
+}
+
+// allow takes a token for a retry, reporting false if the budget is exhausted
+func (b *RetryBudget) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill adds the tokens accumulated since the last refill, up to the capacity
+func (b *RetryBudget) refill() {
+	now := time.Now()
+	b.tokens += now.Sub(b.lastRefill).Seconds() * b.refillPerSecond
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.lastRefill = now
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// RetryOnTransientNetworkErrors is a RetryIfFunc that only retries transient network failures:
+// timeouts, refused or reset connections and unexpected EOFs.
+// Permanent failures such as DNS resolution errors, malformed URLs and HTTP status errors are not retried.
+func RetryOnTransientNetworkErrors(err error) bool {
+	return isTransientNetworkError(err)
+}
+
+// isTransientNetworkError reports whether err is a network failure that may succeed when retried
+func isTransientNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+// WithRetryOnConnectionErrors only retries transient network failures, see RetryOnTransientNetworkErrors.
+func WithRetryOnConnectionErrors() RetryOption {
+	return WithRetryIf(RetryOnTransientNetworkErrors)
+}
@@ -0,0 +1,47 @@
+package models
+
+import "sync/atomic"
+
+// RetryStats aggregates retry counters across every request sent through an HttpClient.
+// It is safe for concurrent use and can be shared by several clients.
+type RetryStats struct {
+	attempts atomic.Uint64
+	retries  atomic.Uint64
+	failures atomic.Uint64
+}
+
+// RetryStatsSnapshot holds the totals of a RetryStats at a point in time
+type RetryStatsSnapshot struct {
+	Attempts uint64 // requests sent, including retries
+	Retries  uint64 // attempts after the first one of a request
+	Failures uint64 // requests that failed after exhausting their retries
+}
+
+// NewRetryStats creates an empty RetryStats
+func NewRetryStats() *RetryStats {
+	return &RetryStats{}
+}
+
+// Snapshot returns the current totals
+func (s *RetryStats) Snapshot() RetryStatsSnapshot {
+	return RetryStatsSnapshot{
+		Attempts: s.attempts.Load(),
+		Retries:  s.retries.Load(),
+		Failures: s.failures.Load(),
+	}
+}
+
+// recordAttempt counts an attempt, and a retry if it is not the first attempt of the request
+func (s *RetryStats) recordAttempt(attempt uint) {
+	s.attempts.Add(1)
+	if attempt > 1 {
+		s.retries.Add(1)
+	}
+}
+
+// recordResult counts a failed request
+func (s *RetryStats) recordResult(err error) {
+	if err != nil {
+		s.failures.Add(1)
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import "net/http"
+
+// RetryValue retries fn with the backoff, jitter, context and limits of the retry options, like Retry does
+// for HTTP requests, and returns the value of the first successful call. It can retry token exchanges,
+// polls or any other operation. retryIf decides which errors are retried; when it is nil, the condition
+// set with WithRetryIf is used, and otherwise every error is retried, since the errors of such operations
+// are rarely classified by ClassifyError. Options that only apply to HTTP responses are ignored.
+func RetryValue[T any](fn func() (T, error), retryIf func(error) bool, options ...RetryOption) (T, error) {
+	var value T
+
+	// Prepended so that a condition set with WithRetryIf takes precedence
+	options = append([]RetryOption{WithRetryIf(retryAnyError)}, options...)
+	options = append(options, withoutResponseChecks)
+	if retryIf != nil {
+		options = append(options, WithRetryIf(retryIf))
+	}
+
+	_, err := Retry(func() (*http.Response, error) {
+		result, err := fn()
+		if err != nil {
+			return nil, err
+		}
+		value = result
+		// Any 2xx response is a success for Retry
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}, options...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return value, nil
+}
+
+// retryAnyError retries every error, the default condition of RetryValue
+func retryAnyError(err error) bool {
+	return err != nil
+}
+
+// withoutResponseChecks disables the options that inspect HTTP responses, which RetryValue does not have
+func withoutResponseChecks(cfg *RetryConfig) {
+	cfg.responseValidator = nil
+	cfg.retryIncompleteJSON = false
+	cfg.rawResponses = false
+}
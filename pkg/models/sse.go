@@ -0,0 +1,79 @@
+package models
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// DefaultMaxStreamEventSize is the default maximum size of a single server-sent event line
+const DefaultMaxStreamEventSize = 1 << 20 // 1 MiB
+
+// sseEvent is a server-sent event, with the data of its data lines joined by newlines
+type sseEvent struct {
+	Event string
+	Data  string
+}
+
+// sseReader reads server-sent events, regardless of how they are split across network reads
+type sseReader struct {
+	scanner *bufio.Scanner
+}
+
+// newSSEReader creates a reader that fails on lines longer than maxLineSize bytes
+func newSSEReader(r io.Reader, maxLineSize int) *sseReader {
+	if maxLineSize <= 0 {
+		maxLineSize = DefaultMaxStreamEventSize
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(64*1024, maxLineSize)), maxLineSize)
+
+	return &sseReader{scanner: scanner}
+}
+
+// Next returns the next event with data, or io.EOF at the end of the stream.
+// Events are dispatched on blank lines; an event left unterminated at the end of the stream is still returned.
+func (r *sseReader) Next() (sseEvent, error) {
+	var event sseEvent
+	var data []string
+
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+
+		if line == "" {
+			if len(data) > 0 {
+				event.Data = strings.Join(data, "\n")
+				return event, nil
+			}
+			event = sseEvent{}
+			continue
+		}
+
+		// Lines starting with a colon are comments
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return sseEvent{}, err
+	}
+
+	if len(data) > 0 {
+		event.Data = strings.Join(data, "\n")
+		return event, nil
+	}
+
+	return sseEvent{}, io.EOF
+}
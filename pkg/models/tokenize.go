@@ -0,0 +1,120 @@
+package models
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	TokenizeEndpoint string = "/ml/v1/text/tokenization"
+)
+
+// Tokenizer counts the tokens of a text, e.g. with a local tokenizer matching the model,
+// to estimate the input tokens of a generation without a round trip to watsonx
+type Tokenizer interface {
+	CountTokens(text string) (int, error)
+}
+
+type TokenizeParameters struct {
+	ReturnTokens bool `json:"return_tokens"`
+}
+
+type TokenizePayload struct {
+	ProjectID  string              `json:"project_id,omitempty"`
+	SpaceID    string              `json:"space_id,omitempty"`
+	Model      string              `json:"model_id"`
+	Input      string              `json:"input"`
+	Parameters *TokenizeParameters `json:"parameters,omitempty"`
+}
+
+// TokenizeResponse holds the token count of the input and, when requested, its tokens
+type TokenizeResponse struct {
+	Model  string         `json:"model_id"`
+	Result TokenizeResult `json:"result"`
+}
+
+type TokenizeResult struct {
+	TokenCount int64    `json:"token_count"`
+	Tokens     []string `json:"tokens,omitempty"`
+}
+
+// Tokenize splits the text into the tokens of the model, falling back to the default model of the client
+func (m *Client) Tokenize(ctx context.Context, model, text string) (*TokenizeResponse, error) {
+	m.CheckAndRefreshToken()
+
+	if text == "" {
+		return nil, errors.New("text cannot be empty")
+	}
+
+	payload := TokenizePayload{
+		ProjectID:  m.projectID,
+		SpaceID:    m.spaceID,
+		Model:      m.modelOrDefault(model),
+		Input:      text,
+		Parameters: &TokenizeParameters{ReturnTokens: true},
+	}
+	if payload.Model == "" {
+		return nil, &RequestError{Op: "select model", Err: errNoModel}
+	}
+
+	httpReq, err := m.newJSONRequest(ctx, TokenizeEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := m.httpClient.DoWithRetry(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+
+	var tokenizeRes TokenizeResponse
+	if err := decodeJSONStream(m.codec, res, &tokenizeRes); err != nil {
+		return nil, err
+	}
+
+	return &tokenizeRes, nil
+}
+
+// ServerTokenizer returns a Tokenizer counting the tokens of the model with Tokenize
+func (m *Client) ServerTokenizer(model string) Tokenizer {
+	return serverTokenizer{client: m, model: model}
+}
+
+// serverTokenizer implements Tokenizer with a tokenization request
+type serverTokenizer struct {
+	client *Client
+	model  string
+}
+
+func (t serverTokenizer) CountTokens(text string) (int, error) {
+	response, err := t.client.Tokenize(context.Background(), t.model, text)
+	if err != nil {
+		return 0, err
+	}
+	return int(response.Result.TokenCount), nil
+}
+
+// CountInputTokens estimates the input tokens of a prompt before generating, e.g. to check its cost or
+// the context window of the model. It uses the Tokenizer set for the model with WithModelTokenizer, or else
+// the one set with WithTokenizer, without any request, and otherwise asks watsonx with Tokenize.
+func (m *Client) CountInputTokens(ctx context.Context, model, prompt string) (int64, error) {
+	if tokenizer := m.tokenizerFor(m.modelOrDefault(model)); tokenizer != nil {
+		count, err := tokenizer.CountTokens(prompt)
+		return int64(count), err
+	}
+
+	response, err := m.Tokenize(ctx, model, prompt)
+	if err != nil {
+		return 0, err
+	}
+	return response.Result.TokenCount, nil
+}
+
+// tokenizerFor returns the local Tokenizer of the model, or nil when tokens are counted by watsonx
+func (m *Client) tokenizerFor(model string) Tokenizer {
+	if tokenizer, ok := m.modelTokenizers[model]; ok {
+		return tokenizer
+	}
+	return m.tokenizer
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// toolNamePattern matches the function names accepted by watsonx tool calling
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// jsonSchemaTypes are the types a JSON schema can declare
+var jsonSchemaTypes = map[string]bool{
+	"object":  true,
+	"array":   true,
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+	"null":    true,
+}
+
+// validateChatTools checks the tools of a chat request before it is sent, so that a malformed definition
+// fails locally with a *RequestError instead of being rejected by watsonx after a round trip
+func validateChatTools(tools []ChatTool) error {
+	names := map[string]bool{}
+	for i, tool := range tools {
+		if err := validateChatTool(tool); err != nil {
+			return &RequestError{Op: "validate tools", Err: fmt.Errorf("tool %d (%q): %w", i, tool.Function.Name, err)}
+		}
+		if names[tool.Function.Name] {
+			return &RequestError{Op: "validate tools", Err: fmt.Errorf("tool %d: duplicate name %q", i, tool.Function.Name)}
+		}
+		names[tool.Function.Name] = true
+	}
+	return nil
+}
+
+func validateChatTool(tool ChatTool) error {
+	if tool.Type != "function" {
+		return fmt.Errorf("unsupported type %q, expected \"function\"", tool.Type)
+	}
+	if tool.Function.Name == "" {
+		return errors.New("missing function name")
+	}
+	if !toolNamePattern.MatchString(tool.Function.Name) {
+		return errors.New("function name must be 1 to 64 letters, digits, '_' or '-'")
+	}
+	if tool.Function.Parameters == nil {
+		return nil
+	}
+
+	schema, err := decodeToolSchema(tool.Function.Parameters)
+	if err != nil {
+		return err
+	}
+	if schemaType, ok := schema["type"]; ok && schemaType != "object" {
+		return fmt.Errorf("parameters schema must have type \"object\", got %v", schemaType)
+	}
+	return validateJSONSchema(schema, "parameters")
+}
+
+// decodeToolSchema decodes the parameters of a tool, given as a Go value or as raw JSON, into a JSON object
+func decodeToolSchema(parameters interface{}) (map[string]interface{}, error) {
+	var raw []byte
+	switch p := parameters.(type) {
+	case json.RawMessage:
+		raw = p
+	case []byte:
+		raw = p
+	case string:
+		raw = []byte(p)
+	default:
+		var err error
+		if raw, err = json.Marshal(p); err != nil {
+			return nil, fmt.Errorf("parameters schema cannot be encoded: %w", err)
+		}
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("parameters schema is not a JSON object: %w", err)
+	}
+	if schema == nil {
+		return nil, errors.New("parameters schema is not a JSON object")
+	}
+	return schema, nil
+}
+
+// validateJSONSchema checks the structure of the keywords of a schema used by tool definitions:
+// type, properties, required and items. Other keywords are left to the server.
+func validateJSONSchema(schema map[string]interface{}, path string) error {
+	if schemaType, ok := schema["type"]; ok {
+		if err := validateSchemaType(schemaType); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var properties map[string]interface{}
+	if value, ok := schema["properties"]; ok {
+		if properties, ok = value.(map[string]interface{}); !ok {
+			return fmt.Errorf("%s: properties must be an object", path)
+		}
+		for name, property := range properties {
+			propertySchema, ok := property.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s.%s: schema must be an object", path, name)
+			}
+			if err := validateJSONSchema(propertySchema, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if value, ok := schema["required"]; ok {
+		required, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: required must be an array of property names", path)
+		}
+		for _, item := range required {
+			name, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("%s: required must be an array of property names", path)
+			}
+			if properties != nil {
+				if _, found := properties[name]; !found {
+					return fmt.Errorf("%s: required property %q is not defined", path, name)
+				}
+			}
+		}
+	}
+
+	if value, ok := schema["items"]; ok {
+		items, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: items must be an object", path)
+		}
+		if err := validateJSONSchema(items, path+"[]"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateSchemaType checks a type keyword, which is a type name or an array of type names
+func validateSchemaType(schemaType interface{}) error {
+	switch t := schemaType.(type) {
+	case string:
+		if !jsonSchemaTypes[t] {
+			return fmt.Errorf("unknown type %q", t)
+		}
+		return nil
+	case []interface{}:
+		for _, item := range t {
+			name, ok := item.(string)
+			if !ok || !jsonSchemaTypes[name] {
+				return fmt.Errorf("unknown type %v", item)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("type must be a string or an array of strings, got %v", schemaType)
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ConnectionTrace holds the phase timings of a single request attempt.
+// Phases that did not happen, such as DNS on a reused connection, are zero.
+type ConnectionTrace struct {
+	DNS             time.Duration // DNS lookup
+	Connect         time.Duration // TCP connection
+	TLSHandshake    time.Duration // TLS handshake
+	TimeToFirstByte time.Duration // From sending the request to the first response byte
+	Total           time.Duration // From starting the attempt to receiving the response headers
+
+	ConnReused bool // The connection was reused from the pool
+	WasIdle    bool // The reused connection was idle
+}
+
+// ConnectionTraceFunc receives the trace of each request attempt
+type ConnectionTraceFunc func(req *http.Request, trace ConnectionTrace)
+
+// connectionTraceMiddleware attaches an httptrace.ClientTrace to each attempt and reports its timings
+func connectionTraceMiddleware(report ConnectionTraceFunc) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			var mu sync.Mutex
+			var result ConnectionTrace
+			var dnsStart, connectStart, tlsStart, wroteRequest time.Time
+			start := time.Now()
+
+			trace := &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					mu.Lock()
+					defer mu.Unlock()
+					result.ConnReused = info.Reused
+					result.WasIdle = info.WasIdle
+				},
+				DNSStart: func(httptrace.DNSStartInfo) {
+					mu.Lock()
+					defer mu.Unlock()
+					dnsStart = time.Now()
+				},
+				DNSDone: func(httptrace.DNSDoneInfo) {
+					mu.Lock()
+					defer mu.Unlock()
+					result.DNS = time.Since(dnsStart)
+				},
+				ConnectStart: func(string, string) {
+					mu.Lock()
+					defer mu.Unlock()
+					connectStart = time.Now()
+				},
+				ConnectDone: func(string, string, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					result.Connect = time.Since(connectStart)
+				},
+				TLSHandshakeStart: func() {
+					mu.Lock()
+					defer mu.Unlock()
+					tlsStart = time.Now()
+				},
+				TLSHandshakeDone: func(tls.ConnectionState, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					result.TLSHandshake = time.Since(tlsStart)
+				},
+				WroteRequest: func(httptrace.WroteRequestInfo) {
+					mu.Lock()
+					defer mu.Unlock()
+					wroteRequest = time.Now()
+				},
+				GotFirstResponseByte: func() {
+					mu.Lock()
+					defer mu.Unlock()
+					if !wroteRequest.IsZero() {
+						result.TimeToFirstByte = time.Since(wroteRequest)
+					}
+				},
+			}
+
+			resp, err := next.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+
+			mu.Lock()
+			result.Total = time.Since(start)
+			reported := result
+			mu.Unlock()
+
+			report(req, reported)
+			return resp, err
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 type (
 	WatsonxAPIKey    = string
 	WatsonxProjectID = string
+	WatsonxSpaceID   = string
 	IBMCloudRegion   = string
 	ModelType        = string
 )
@@ -18,6 +19,12 @@ const (
 	WatsonxAPIKeyEnvVarName    = "WATSONX_API_KEY"
 	WatsonxProjectIDEnvVarName = "WATSONX_PROJECT_ID"
 
+	// Read by NewClientFromEnv only
+	WatsonxSpaceIDEnvVarName    = "WATSONX_SPACE_ID"
+	WatsonxBaseURLEnvVarName    = "WATSONX_URL" // Full base URL, such as 'https://us-south.ml.cloud.ibm.com'
+	WatsonxRegionEnvVarName     = "WATSONX_REGION"
+	WatsonxAPIVersionEnvVarName = "WATSONX_API_VERSION"
+
 	US_South  IBMCloudRegion = "us-south"
 	Dallas    IBMCloudRegion = US_South
 	EU_DE     IBMCloudRegion = "eu-de"
@@ -25,9 +32,20 @@ const (
 	JP_TOK    IBMCloudRegion = "jp-tok"
 	Tokyo     IBMCloudRegion = JP_TOK
 
+	RegionDallas    IBMCloudRegion = US_South
+	RegionFrankfurt IBMCloudRegion = EU_DE
+	RegionTokyo     IBMCloudRegion = JP_TOK
+	RegionLondon    IBMCloudRegion = "eu-gb"
+	RegionSydney    IBMCloudRegion = "au-syd"
+	RegionToronto   IBMCloudRegion = "ca-tor"
+
 	DefaultRegion     = US_South
 	BaseURLFormatStr  = "%s.ml.cloud.ibm.com" // Need to call SPrintf on it with region
 	DefaultAPIVersion = "2024-05-20"
+	APIVersionLayout  = "2006-01-02" // API versions are dates, sent as the version query parameter
+
+	Version          = "1.0.0" // watsonx-go version
+	DefaultUserAgent = "watsonx-go/" + Version
 )
 
 type Doer interface {